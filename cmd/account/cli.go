@@ -48,11 +48,10 @@ func (o *cliOptions) complete(cmd *cobra.Command) error {
 
 	var err error
 
-	ocmClient, err := utils.CreateConnection()
+	ocmClient, err := utils.SharedConnection()
 	if err != nil {
 		return err
 	}
-	defer ocmClient.Close()
 
 	if o.awsAccountID == "" {
 		return fmt.Errorf("please specify account number with '-i'")
@@ -69,11 +68,10 @@ func (o *cliOptions) run() error {
 
 	var err error
 
-	ocmClient, err := utils.CreateConnection()
+	ocmClient, err := utils.SharedConnection()
 	if err != nil {
 		return err
 	}
-	defer ocmClient.Close()
 
 	// Build the base AWS client using the provide credentials (profile or env vars)
 	awsClient, err := aws.NewAwsClient(o.awsProfile, o.region, "")
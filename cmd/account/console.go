@@ -72,11 +72,10 @@ func (o *consoleOptions) run() error {
 
 	var err error
 
-	ocmClient, err := utils.CreateConnection()
+	ocmClient, err := utils.SharedConnection()
 	if err != nil {
 		return err
 	}
-	defer ocmClient.Close()
 
 	// Build the base AWS client using the provide credentials (profile or env vars)
 	awsClient, err := aws.NewAwsClient(o.awsProfile, o.region, "")
@@ -152,17 +152,11 @@ func AddAlertNameSilence(alertID []string, duration, comment, username string, k
 
 // Get User name and clustername
 func GetUserAndClusterInfo(clusterid string) (string, string) {
-	connection, err := ocmutils.CreateConnection()
+	connection, err := ocmutils.SharedConnection()
 	if err != nil {
 		fmt.Printf("Error %s in create connection.", err)
 	}
 
-	defer func() {
-		if cerr := connection.Close(); cerr != nil {
-			fmt.Println("Error closing connection:", cerr)
-		}
-	}()
-
 	cluster, err := ocmutils.GetCluster(connection, clusterid)
 	if err != nil {
 		fmt.Printf("Error %s in getting cluster.", err)
@@ -56,7 +56,7 @@ func AddOrgSilence(cmd *AddOrgSilenceCmd) {
 		log.Fatal("No subscriptions found with that organization ID")
 	}
 
-	connection, err := ocmutils.CreateConnection()
+	connection, err := ocmutils.SharedConnection()
 	if err != nil {
 		log.Fatal(err)
 	}
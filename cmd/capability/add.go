@@ -68,15 +68,10 @@ func (o *addOptions) complete(cmd *cobra.Command, args []string) error {
 
 func (o *addOptions) run(cmd *cobra.Command, capability string) error {
 	// Initalize OCM connection
-	ocmClient, err := utils.CreateConnection()
+	ocmClient, err := utils.SharedConnection()
 	if err != nil {
 		return err
 	}
-	defer func() {
-		if err := ocmClient.Close(); err != nil {
-			fmt.Printf("Cannot close the ocmClient (possible memory leak): %q", err)
-		}
-	}()
 
 	body := CapabilityBody{Internal: true, Value: "true"}
 
@@ -67,11 +67,10 @@ func (p *permissionDeniedEventsOptions) run() error {
 		return err
 	}
 
-	connection, err := utils.CreateConnection()
+	connection, err := utils.SharedConnection()
 	if err != nil {
 		return fmt.Errorf("unable to create connection to ocm: %w", err)
 	}
-	defer connection.Close()
 
 	cluster, err := utils.GetClusterAnyStatus(connection, p.ClusterID)
 	if err != nil {
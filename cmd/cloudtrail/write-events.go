@@ -102,11 +102,10 @@ func (o *writeEventsOptions) run() error {
 	if err != nil {
 		return err
 	}
-	connection, err := utils.CreateConnection()
+	connection, err := utils.SharedConnection()
 	if err != nil {
 		return fmt.Errorf("unable to create connection to ocm: %w", err)
 	}
-	defer connection.Close()
 
 	cluster, err := utils.GetClusterAnyStatus(connection, o.ClusterID)
 	if err != nil {
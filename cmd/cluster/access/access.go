@@ -125,13 +125,10 @@ func (c *clusterAccessOptions) Run(cmd *cobra.Command, args []string) error {
 	c.Println(fmt.Sprintf("Retrieving Kubeconfig for cluster '%s'", clusterIdentifier))
 
 	// Connect to ocm
-	conn, err := osdctlutil.CreateConnection()
+	conn, err := osdctlutil.SharedConnection()
 	if err != nil {
 		return err
 	}
-	defer func() {
-		cmdutil.CheckErr(conn.Close())
-	}()
 
 	cluster, err := osdctlutil.GetCluster(conn, clusterIdentifier)
 	if err != nil {
@@ -88,13 +88,10 @@ func (c *cleanupAccessOptions) Readln() (string, error) {
 func (c *cleanupAccessOptions) Run(cmd *cobra.Command, args []string) error {
 	clusterIdentifier := args[0]
 
-	conn, err := osdctlutil.CreateConnection()
+	conn, err := osdctlutil.SharedConnection()
 	if err != nil {
 		return err
 	}
-	defer func() {
-		cmdutil.CheckErr(conn.Close())
-	}()
 
 	cluster, err := osdctlutil.GetCluster(conn, clusterIdentifier)
 	if err != nil {
@@ -22,15 +22,10 @@ func newCmdCheckBannedUser() *cobra.Command {
 }
 
 func CheckBannedUser(clusterID string) error {
-	ocm, err := utils.CreateConnection()
+	ocm, err := utils.SharedConnection()
 	if err != nil {
 		return err
 	}
-	defer func() {
-		if ocmCloseErr := ocm.Close(); ocmCloseErr != nil {
-			fmt.Printf("Cannot close the ocm (possible memory leak): %q", ocmCloseErr)
-		}
-	}()
 
 	fmt.Print("Finding subscription account: ")
 	subscription, err := utils.GetSubscription(ocm, clusterID)
@@ -68,11 +68,10 @@ func newCmdCleanupLeakedEC2() *cobra.Command {
 
 func (c *cleanup) New(ctx context.Context) error {
 	log.Printf("searching OCM for cluster: %s", c.ClusterId)
-	conn, err := utils.CreateConnection()
+	conn, err := utils.SharedConnection()
 	if err != nil {
 		return err
 	}
-	defer conn.Close()
 
 	cluster, err := utils.GetClusterAnyStatus(conn, c.ClusterId)
 	if err != nil {
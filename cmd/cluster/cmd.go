@@ -30,7 +30,7 @@ func NewCmdCluster(streams genericclioptions.IOStreams, client *k8s.LazyClient,
 	clusterCmd.AddCommand(support.NewCmdSupport(streams, client, globalOpts))
 	clusterCmd.AddCommand(resize.NewCmdResize())
 	clusterCmd.AddCommand(newCmdResync())
-	clusterCmd.AddCommand(newCmdContext())
+	clusterCmd.AddCommand(newCmdContext(globalOpts))
 	clusterCmd.AddCommand(newCmdTransferOwner(streams, globalOpts))
 	clusterCmd.AddCommand(access.NewCmdAccess(streams, client))
 	clusterCmd.AddCommand(newCmdCpd())
@@ -21,13 +21,16 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/cloudtrail/types"
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	"github.com/openshift/osdctl/cmd/cluster/dynatrace"
+	"github.com/openshift/osdctl/internal/utils/globalflags"
 	"github.com/openshift/osdctl/pkg/osdCloud"
 	"github.com/openshift/osdctl/pkg/osdctlConfig"
 	"github.com/openshift/osdctl/pkg/printer"
 	"github.com/openshift/osdctl/pkg/provider/pagerduty"
 	"github.com/openshift/osdctl/pkg/utils"
+	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+	"golang.org/x/term"
 	cmdutil "k8s.io/kubectl/pkg/cmd/util"
 )
 
@@ -43,17 +46,23 @@ const (
 	delimiter                     = ">> "
 )
 
+// contextOutputFormats are the values of the global --output flag supported by the context command
+var contextOutputFormats = []string{longOutputConfigValue, shortOutputConfigValue, jsonOutputConfigValue}
+
 type contextOptions struct {
-	cluster *cmv1.Cluster
+	cluster       *cmv1.Cluster
+	globalOptions *globalflags.GlobalOptions
 
 	output            string
 	verbose           bool
 	full              bool
+	pretty            bool
 	clusterID         string
 	externalClusterID string
 	baseDomain        string
 	organizationID    string
 	days              int
+	slWeeks           int
 	pages             int
 	oauthtoken        string
 	usertoken         string
@@ -61,6 +70,9 @@ type contextOptions struct {
 	awsProfile        string
 	jiratoken         string
 	team_ids          []string
+	pdSubdomain       string
+	fields            []string
+	selectedFields    []contextField
 }
 
 type contextData struct {
@@ -80,13 +92,15 @@ type contextData struct {
 	LimitedSupportReasons []*cmv1.LimitedSupportReason
 	// Service Logs
 	ServiceLogs []*v1.LogEntry
+	// Weekly Service Log counts of the past --sl-weeks weeks, oldest first
+	ServiceLogWeeklyCounts []int
 
 	// Jira Cards
 	JiraIssues        []jira.Issue
 	SupportExceptions []jira.Issue
 
 	// PD Alerts
-	pdServiceID      []string
+	pdServices       []pagerduty.ServiceLink
 	PdAlerts         map[string][]pd.Incident
 	HistoricalAlerts map[string][]*pagerduty.IncidentOccurrenceTracker
 
@@ -97,12 +111,22 @@ type contextData struct {
 	Description string
 }
 
+// pdServiceIDs returns the IDs of the PagerDuty services of the cluster
+func (data *contextData) pdServiceIDs() []string {
+	var ids []string
+	for _, service := range data.pdServices {
+		ids = append(ids, service.ID)
+	}
+	return ids
+}
+
 // newCmdContext implements the context command to show the current context of a cluster
-func newCmdContext() *cobra.Command {
-	ops := newContextOptions()
+func newCmdContext(globalOpts *globalflags.GlobalOptions) *cobra.Command {
+	ops := newContextOptions(globalOpts)
 	contextCmd := &cobra.Command{
 		Use:               "context",
 		Short:             "Shows the context of a specified cluster",
+		Long:              "Shows the context of a specified cluster.\n\nThe global --output flag supports the 'long', 'short' and 'json' formats. Output is set to 'long' by default",
 		Args:              cobra.ExactArgs(1),
 		DisableAutoGenTag: true,
 		Run: func(cmd *cobra.Command, args []string) {
@@ -111,22 +135,26 @@ func newCmdContext() *cobra.Command {
 		},
 	}
 
-	contextCmd.Flags().StringVarP(&ops.output, "output", "o", "long", "Valid formats are ['long', 'short', 'json']. Output is set to 'long' by default")
+	globalflags.SetOutputFormats(contextCmd, contextOutputFormats...)
 	contextCmd.Flags().StringVarP(&ops.clusterID, "cluster-id", "C", "", "Cluster ID")
 	contextCmd.Flags().StringVarP(&ops.awsProfile, "profile", "p", "", "AWS Profile")
 	contextCmd.Flags().BoolVarP(&ops.verbose, "verbose", "", false, "Verbose output")
 	contextCmd.Flags().BoolVar(&ops.full, "full", false, "Run full suite of checks.")
+	contextCmd.Flags().StringSliceVar(&ops.fields, "fields", nil, "Only print the given comma separated overview fields, i.e. version,region,state. Skips collecting the rest of the context")
+	contextCmd.Flags().BoolVar(&ops.pretty, "pretty", false, "Indent the json output. Enabled by default when stdout is a terminal, use --pretty=false for compact output to pipe into jq")
 	contextCmd.Flags().IntVarP(&ops.days, "days", "d", 30, "Command will display X days of Error SLs sent to the cluster. Days is set to 30 by default")
+	contextCmd.Flags().IntVar(&ops.slWeeks, "sl-weeks", 0, "Show a weekly trend of the Error SLs sent to the cluster in the past X weeks, regardless of --days")
 	contextCmd.Flags().IntVar(&ops.pages, "pages", 40, "Command will display X pages of Cloud Trail logs for the cluster. Pages is set to 40 by default")
 	contextCmd.Flags().StringVar(&ops.oauthtoken, "oauthtoken", "", fmt.Sprintf("Pass in PD oauthtoken directly. If not passed in, by default will read `pd_oauth_token` from ~/.config/%s.\nPD OAuth tokens can be generated by visiting %s", osdctlConfig.ConfigFileName, PagerDutyTokenRegistrationUrl))
 	contextCmd.Flags().StringVar(&ops.usertoken, "usertoken", "", fmt.Sprintf("Pass in PD usertoken directly. If not passed in, by default will read `pd_user_token` from ~/config/%s", osdctlConfig.ConfigFileName))
 	contextCmd.Flags().StringVar(&ops.jiratoken, "jiratoken", "", fmt.Sprintf("Pass in the Jira access token directly. If not passed in, by default will read `jira_token` from ~/.config/%s.\nJira access tokens can be registered by visiting %s/%s", osdctlConfig.ConfigFileName, JiraBaseURL, JiraTokenRegistrationPath))
 	contextCmd.Flags().StringArrayVarP(&ops.team_ids, "team-ids", "t", []string{}, fmt.Sprintf("Pass in PD team IDs directly to filter the PD Alerts by team. Can also be defined as `team_ids` in ~/.config/%s\nWill show all PD Alerts for all PD service IDs if none is defined", osdctlConfig.ConfigFileName))
+	contextCmd.Flags().StringVar(&ops.pdSubdomain, "pd-subdomain", pagerduty.Subdomain(), fmt.Sprintf("PagerDuty account subdomain used in links to PD services. Can also be set with the %s environment variable", pagerduty.SubdomainEnv))
 	return contextCmd
 }
 
-func newContextOptions() *contextOptions {
-	return &contextOptions{}
+func newContextOptions(globalOpts *globalflags.GlobalOptions) *contextOptions {
+	return &contextOptions{globalOptions: globalOpts}
 }
 
 func (o *contextOptions) complete(cmd *cobra.Command, args []string) error {
@@ -138,17 +166,32 @@ func (o *contextOptions) complete(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("cannot have a days value lower than 1")
 	}
 
+	if o.slWeeks < 0 {
+		return fmt.Errorf("cannot have a sl-weeks value lower than 0")
+	}
+
+	o.output = o.globalOptions.Output
+	if o.output == "" {
+		o.output = longOutputConfigValue
+	}
+	if err := globalflags.ValidateOutput(o.output, contextOutputFormats...); err != nil {
+		return err
+	}
+	var err error
+	o.selectedFields, err = parseContextFields(o.fields)
+	if err != nil {
+		return err
+	}
+	if !cmd.Flags().Changed("pretty") {
+		o.pretty = term.IsTerminal(int(os.Stdout.Fd()))
+	}
+
 	// Create OCM client to talk to cluster API
 	defer utils.StartDelayTracker(o.verbose, "OCM Clusters").End()
-	ocmClient, err := utils.CreateConnection()
+	ocmClient, err := utils.SharedConnection()
 	if err != nil {
 		return err
 	}
-	defer func() {
-		if err := ocmClient.Close(); err != nil {
-			fmt.Printf("Cannot close the ocmClient (possible memory leak): %q", err)
-		}
-	}()
 
 	clusters := utils.GetClusters(ocmClient, args)
 	if len(clusters) != 1 {
@@ -171,7 +214,7 @@ func (o *contextOptions) complete(cmd *cobra.Command, args []string) error {
 
 	orgID, err := utils.GetOrgfromClusterID(ocmClient, *o.cluster)
 	if err != nil {
-		fmt.Printf("Failed to get Org ID for cluster ID %s - err: %q", o.clusterID, err)
+		log.Warnf("Failed to get Org ID for cluster ID %s - err: %q", o.clusterID, err)
 		o.organizationID = ""
 	} else {
 		o.organizationID = orgID
@@ -181,6 +224,10 @@ func (o *contextOptions) complete(cmd *cobra.Command, args []string) error {
 }
 
 func (o *contextOptions) run() error {
+	if len(o.selectedFields) > 0 {
+		return o.printFields(os.Stdout, o.selectedFields)
+	}
+
 	var printFunc func(*contextData)
 	switch o.output {
 	case shortOutputConfigValue:
@@ -195,14 +242,13 @@ func (o *contextOptions) run() error {
 
 	currentData, dataErrors := o.generateContextData()
 	if currentData == nil {
-		fmt.Fprintf(os.Stderr, "Failed to query cluster info: %+v", dataErrors)
-		os.Exit(1)
+		log.Fatalf("Failed to query cluster info: %+v", dataErrors)
 	}
 
 	if len(dataErrors) > 0 {
-		fmt.Fprintf(os.Stderr, "Encountered Errors during data collection. Displayed data may be incomplete: \n")
+		log.Warnf("Encountered Errors during data collection. Displayed data may be incomplete:")
 		for _, dataError := range dataErrors {
-			fmt.Fprintf(os.Stderr, "\t%v\n", dataError)
+			log.Warnf("\t%v", dataError)
 		}
 	}
 
@@ -218,17 +264,25 @@ func (o *contextOptions) printLongOutput(data *contextData) {
 	fmt.Println()
 	utils.PrintLimitedSupportReasons(data.LimitedSupportReasons)
 	fmt.Println()
+	if len(data.LimitedSupportReasons) > 0 {
+		printLimitedSupportServiceLogs(data.LimitedSupportReasons, data.ServiceLogs)
+		fmt.Println()
+	}
 	printJIRASupportExceptions(data.SupportExceptions)
 	fmt.Println()
 	utils.PrintServiceLogs(data.ServiceLogs, o.verbose, o.days)
 	fmt.Println()
+	if o.slWeeks > 0 {
+		printServiceLogTrend(data.ServiceLogWeeklyCounts, time.Now())
+		fmt.Println()
+	}
 	utils.PrintJiraIssues(data.JiraIssues)
 	fmt.Println()
-	utils.PrintPDAlerts(data.PdAlerts, data.pdServiceID)
+	utils.PrintPDAlerts(data.PdAlerts, data.pdServices)
 	fmt.Println()
 
 	if o.full {
-		printHistoricalPDAlertSummary(data.HistoricalAlerts, data.pdServiceID, o.days)
+		printHistoricalPDAlertSummary(data.HistoricalAlerts, data.pdServices, o.days)
 		fmt.Println()
 
 		printCloudTrailLogs(data.CloudtrailEvents)
@@ -295,14 +349,20 @@ func (o *contextOptions) printShortOutput(data *contextData) {
 	})
 
 	if err := table.Flush(); err != nil {
-		fmt.Fprintf(os.Stderr, "Error printing Short Output: %v\n", err)
+		log.Errorf("Error printing Short Output: %v", err)
 	}
 }
 
 func (o *contextOptions) printJsonOutput(data *contextData) {
-	jsonOut, err := json.MarshalIndent(data, "", "  ")
+	var jsonOut []byte
+	var err error
+	if o.pretty {
+		jsonOut, err = json.MarshalIndent(data, "", "  ")
+	} else {
+		jsonOut, err = json.Marshal(data)
+	}
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Can't marshal results to json: %v\n", err)
+		log.Errorf("Can't marshal results to json: %v", err)
 		return
 	}
 
@@ -336,11 +396,10 @@ func (o *contextOptions) generateContextData() (*contextData, []error) {
 		errors = append(errors, fmt.Errorf("skipping PagerDuty context collection: %v", err))
 	}
 
-	ocmClient, err := utils.CreateConnection()
+	ocmClient, err := utils.SharedConnection()
 	if err != nil {
 		return nil, []error{err}
 	}
-	defer ocmClient.Close()
 	// Normally the o.cluster would be set by complete function, but in case we want to call this function
 	// in an other context, we can make sure o.cluster is set properly from o.clusterID
 	if o.cluster == nil {
@@ -371,10 +430,26 @@ func (o *contextOptions) generateContextData() (*contextData, []error) {
 	GetServiceLogs := func() {
 		defer wg.Done()
 		defer utils.StartDelayTracker(o.verbose, "Service Logs").End()
-		timeToCheckSvcLogs := time.Now().AddDate(0, 0, -o.days)
-		data.ServiceLogs, err = servicelog.GetServiceLogsSince(o.clusterID, timeToCheckSvcLogs, false, false)
+		now := time.Now()
+		timeToCheckSvcLogs := now.AddDate(0, 0, -o.days)
+		// The trend may reach further back than --days, fetch the service logs once for both
+		timeToFetchSvcLogs := timeToCheckSvcLogs
+		if trendStart := now.AddDate(0, 0, -7*o.slWeeks); trendStart.Before(timeToFetchSvcLogs) {
+			timeToFetchSvcLogs = trendStart
+		}
+		serviceLogs, err := servicelog.GetServiceLogsSince(o.clusterID, timeToFetchSvcLogs, false, false)
 		if err != nil {
 			errors = append(errors, fmt.Errorf("error while getting the service logs: %v", err))
+			return
+		}
+
+		if o.slWeeks > 0 {
+			data.ServiceLogWeeklyCounts = weeklyServiceLogCounts(serviceLogs, now, o.slWeeks)
+		}
+		for _, serviceLog := range serviceLogs {
+			if serviceLog.CreatedAt().After(timeToCheckSvcLogs) {
+				data.ServiceLogs = append(data.ServiceLogs, serviceLog)
+			}
 		}
 	}
 
@@ -434,14 +509,14 @@ func (o *contextOptions) generateContextData() (*contextData, []error) {
 		}
 
 		delayTracker := utils.StartDelayTracker(o.verbose, "PagerDuty Service")
-		data.pdServiceID, err = pdProvider.GetPDServiceIDs()
+		data.pdServices, err = pdProvider.GetClusterServiceURLs(o.clusterID, o.externalClusterID, o.pdSubdomain)
 		if err != nil {
-			errors = append(errors, fmt.Errorf("error getting PD Service ID: %v", err))
+			errors = append(errors, fmt.Errorf("error getting PD Services: %v", err))
 		}
 		delayTracker.End()
 
 		defer utils.StartDelayTracker(o.verbose, "current PagerDuty Alerts").End()
-		data.PdAlerts, err = pdProvider.GetFiringAlertsForCluster(data.pdServiceID)
+		data.PdAlerts, err = pdProvider.GetFiringAlertsForCluster(data.pdServiceIDs())
 		if err != nil {
 			errors = append(errors, fmt.Errorf("error while getting current PD Alerts: %v", err))
 		}
@@ -468,8 +543,7 @@ func (o *contextOptions) generateContextData() (*contextData, []error) {
 			cmd := "ocm describe cluster " + o.clusterID
 			output, err := exec.Command("bash", "-c", cmd).Output()
 			if err != nil {
-				fmt.Fprintln(os.Stderr, string(output))
-				fmt.Fprintln(os.Stderr, err)
+				log.Errorf("Failed to describe cluster: %v\n%s", err, output)
 			}
 			data.Description = string(output)
 		}
@@ -485,7 +559,7 @@ func (o *contextOptions) generateContextData() (*contextData, []error) {
 			pdwg.Wait()
 			defer wg.Done()
 			defer utils.StartDelayTracker(o.verbose, "historical PagerDuty Alerts").End()
-			data.HistoricalAlerts, err = pdProvider.GetHistoricalAlertsForCluster(data.pdServiceID)
+			data.HistoricalAlerts, err = pdProvider.GetHistoricalAlertsForCluster(data.pdServiceIDs())
 			if err != nil {
 				errors = append(errors, fmt.Errorf("error while getting historical PD Alert Data: %v", err))
 			}
@@ -556,18 +630,19 @@ func GetCloudTrailLogsForCluster(awsProfile string, clusterID string, maxPages i
 	return filteredEvents, nil
 }
 
-func printHistoricalPDAlertSummary(incidentCounters map[string][]*pagerduty.IncidentOccurrenceTracker, serviceIDs []string, sinceDays int) {
+func printHistoricalPDAlertSummary(incidentCounters map[string][]*pagerduty.IncidentOccurrenceTracker, services []pagerduty.ServiceLink, sinceDays int) {
 	var name string = "PagerDuty Historical Alerts"
 	fmt.Println(delimiter + name)
 
-	for _, serviceID := range serviceIDs {
+	for _, service := range services {
+		serviceID := service.ID
 
 		if len(incidentCounters[serviceID]) == 0 {
-			fmt.Println("Service: https://redhat.pagerduty.com/service-directory/" + serviceID + ": None")
+			fmt.Println("Service: " + service.URL + ": None")
 			continue
 		}
 
-		fmt.Println("Service: https://redhat.pagerduty.com/service-directory/" + serviceID + ":")
+		fmt.Println("Service: " + service.URL + ":")
 		table := printer.NewTablePrinter(os.Stdout, 20, 1, 3, ' ')
 		table.AddRow([]string{"Type", "Count", "Last Occurrence"})
 		totalIncidents := 0
@@ -579,7 +654,7 @@ func printHistoricalPDAlertSummary(incidentCounters map[string][]*pagerduty.Inci
 		// Add empty row for readability
 		table.AddRow([]string{})
 		if err := table.Flush(); err != nil {
-			fmt.Fprintf(os.Stderr, "Error printing %s: %v\n", name, err)
+			log.Errorf("Error printing %s: %v", name, err)
 		}
 
 		fmt.Println("\tTotal number of incidents [", totalIncidents, "] in [", sinceDays, "] days")
@@ -610,10 +685,8 @@ func (o *contextOptions) printOtherLinks(data *contextData) {
 		"Splunk Audit Logs": o.buildSplunkURL(data),
 	}
 
-	if data.pdServiceID != nil {
-		for _, id := range data.pdServiceID {
-			links[fmt.Sprintf("PagerDuty Service %s", id)] = fmt.Sprintf("https://redhat.pagerduty.com/service-directory/%s", id)
-		}
+	for _, service := range data.pdServices {
+		links[fmt.Sprintf("PagerDuty Service %s", service.ID)] = service.URL
 	}
 
 	// Sort, so it's always a predictable order
@@ -629,7 +702,7 @@ func (o *contextOptions) printOtherLinks(data *contextData) {
 	}
 
 	if err := table.Flush(); err != nil {
-		fmt.Fprintf(os.Stderr, "Error printing %s: %v\n", name, err)
+		log.Errorf("Error printing %s: %v", name, err)
 	}
 }
 
@@ -677,7 +750,7 @@ func printCloudTrailLogs(events []*types.Event) {
 	// Add empty row for readability
 	table.AddRow([]string{})
 	if err := table.Flush(); err != nil {
-		fmt.Fprintf(os.Stderr, "Error printing %s: %v\n", name, err)
+		log.Errorf("Error printing %s: %v", name, err)
 	}
 }
 
@@ -730,7 +803,7 @@ func printDynatraceResources(data *contextData) {
 	}
 
 	if err := table.Flush(); err != nil {
-		fmt.Fprintf(os.Stderr, "Error printing %s: %v\n", name, err)
+		log.Errorf("Error printing %s: %v", name, err)
 	}
 }
 
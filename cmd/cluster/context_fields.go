@@ -0,0 +1,122 @@
+package cluster
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+)
+
+// contextField is an overview field of the cluster that can be selected with --fields
+type contextField struct {
+	name  string
+	value func(o *contextOptions, cluster *cmv1.Cluster) string
+}
+
+// contextFields are the fields supported by --fields, in the order they are listed in errors
+var contextFields = []contextField{
+	{name: "name", value: func(_ *contextOptions, c *cmv1.Cluster) string { return c.Name() }},
+	{name: "id", value: func(_ *contextOptions, c *cmv1.Cluster) string { return c.ID() }},
+	{name: "external-id", value: func(_ *contextOptions, c *cmv1.Cluster) string { return c.ExternalID() }},
+	{name: "version", value: func(_ *contextOptions, c *cmv1.Cluster) string { return c.Version().RawID() }},
+	{name: "state", value: func(_ *contextOptions, c *cmv1.Cluster) string { return string(c.State()) }},
+	{name: "region", value: func(_ *contextOptions, c *cmv1.Cluster) string { return c.Region().ID() }},
+	{name: "cloud-provider", value: func(_ *contextOptions, c *cmv1.Cluster) string { return c.CloudProvider().ID() }},
+	{name: "product", value: func(_ *contextOptions, c *cmv1.Cluster) string { return c.Product().ID() }},
+	{name: "hcp", value: func(_ *contextOptions, c *cmv1.Cluster) string { return strconv.FormatBool(c.Hypershift().Enabled()) }},
+	{name: "base-domain", value: func(_ *contextOptions, c *cmv1.Cluster) string { return c.DNS().BaseDomain() }},
+	{name: "infra-id", value: func(_ *contextOptions, c *cmv1.Cluster) string { return c.InfraID() }},
+	{name: "org-id", value: func(o *contextOptions, _ *cmv1.Cluster) string { return o.organizationID }},
+}
+
+// fieldValues are the selected fields of the cluster, marshalled to a json object keeping the order of --fields
+type fieldValues []fieldValue
+
+type fieldValue struct {
+	name  string
+	value string
+}
+
+// MarshalJSON writes the fields as a json object, in order
+func (values fieldValues) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, field := range values {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		name, err := json.Marshal(field.name)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(field.value)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// parseContextFields returns the contextFields matching the names given to --fields, in the given order
+func parseContextFields(names []string) ([]contextField, error) {
+	var fields []contextField
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		found := false
+		for _, field := range contextFields {
+			if field.name == name {
+				fields = append(fields, field)
+				found = true
+				break
+			}
+		}
+		if !found {
+			validFields := make([]string, 0, len(contextFields))
+			for _, field := range contextFields {
+				validFields = append(validFields, field.name)
+			}
+			return nil, fmt.Errorf("unknown field '%s', valid fields are ['%s']", name, strings.Join(validFields, "', '"))
+		}
+	}
+	return fields, nil
+}
+
+// printFields prints only the selected fields of the cluster in the order they were given, as a json object with the
+// json output and as 'field: value' lines otherwise
+func (o *contextOptions) printFields(out io.Writer, fields []contextField) error {
+	if o.output == jsonOutputConfigValue {
+		values := make(fieldValues, 0, len(fields))
+		for _, field := range fields {
+			values = append(values, fieldValue{name: field.name, value: field.value(o, o.cluster)})
+		}
+
+		var jsonOut []byte
+		var err error
+		if o.pretty {
+			jsonOut, err = json.MarshalIndent(values, "", "  ")
+		} else {
+			jsonOut, err = json.Marshal(values)
+		}
+		if err != nil {
+			return fmt.Errorf("can't marshal fields to json: %w", err)
+		}
+		_, err = fmt.Fprintln(out, string(jsonOut))
+		return err
+	}
+
+	for _, field := range fields {
+		_, err := fmt.Fprintf(out, "%s: %s\n", field.name, field.value(o, o.cluster))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package cluster
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+)
+
+func TestParseContextFields(t *testing.T) {
+	fields, err := parseContextFields([]string{"version", " Region", "state"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var names []string
+	for _, field := range fields {
+		names = append(names, field.name)
+	}
+	if strings.Join(names, ",") != "version,region,state" {
+		t.Errorf("expected the fields version,region,state, got %v", names)
+	}
+
+	_, err = parseContextFields([]string{"version", "colour"})
+	if err == nil || !strings.Contains(err.Error(), "'colour'") || !strings.Contains(err.Error(), "'region'") {
+		t.Errorf("expected an error naming the unknown field and listing the valid ones, got %v", err)
+	}
+}
+
+func TestPrintFields(t *testing.T) {
+	cluster, err := cmv1.NewCluster().
+		Name("example").
+		State(cmv1.ClusterStateReady).
+		Region(cmv1.NewCloudRegion().ID("us-east-1")).
+		Version(cmv1.NewVersion().RawID("4.15.2")).
+		Build()
+	if err != nil {
+		t.Fatalf("failed to build cluster: %v", err)
+	}
+	fields, err := parseContextFields([]string{"version", "region", "state"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		output   string
+		pretty   bool
+		expected string
+	}{
+		{output: longOutputConfigValue, expected: "version: 4.15.2\nregion: us-east-1\nstate: ready\n"},
+		{output: jsonOutputConfigValue, expected: `{"version":"4.15.2","region":"us-east-1","state":"ready"}` + "\n"},
+		{output: jsonOutputConfigValue, pretty: true, expected: "{\n  \"version\": \"4.15.2\",\n  \"region\": \"us-east-1\",\n  \"state\": \"ready\"\n}\n"},
+	}
+	for _, test := range tests {
+		var out bytes.Buffer
+		o := &contextOptions{cluster: cluster, output: test.output, pretty: test.pretty}
+		if err := o.printFields(&out, fields); err != nil {
+			t.Fatalf("Test '%s' failed. Unexpected error: %v", test.output, err)
+		}
+		if out.String() != test.expected {
+			t.Errorf("Test '%s' failed. Expected '%s', got '%s'", test.output, test.expected, out.String())
+		}
+	}
+}
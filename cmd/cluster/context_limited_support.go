@@ -0,0 +1,88 @@
+package cluster
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	v1 "github.com/openshift-online/ocm-sdk-go/servicelogs/v1"
+)
+
+// limitedSupportStopWords are left out of the keywords matching limited support reasons to service logs, as most
+// reasons and service logs contain them
+var limitedSupportStopWords = map[string]bool{
+	"cluster": true, "clusters": true, "limited": true, "support": true, "action": true, "required": true,
+	"your": true, "with": true, "from": true, "have": true, "this": true, "that": true, "been": true, "will": true,
+}
+
+// limitedSupportKeywords returns the lowercase words of a limited support reason summary used to find related
+// service logs
+func limitedSupportKeywords(summary string) []string {
+	var keywords []string
+	seen := map[string]bool{}
+	for _, word := range strings.FieldsFunc(strings.ToLower(summary), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if len(word) < 4 || limitedSupportStopWords[word] || seen[word] {
+			continue
+		}
+		seen[word] = true
+		keywords = append(keywords, word)
+	}
+	return keywords
+}
+
+// relatedServiceLogs returns the service logs whose summary or description relates to the limited support reason:
+// either one contains the other's summary, or they share at least two keywords (one if the reason only has one)
+func relatedServiceLogs(reason *cmv1.LimitedSupportReason, serviceLogs []*v1.LogEntry) []*v1.LogEntry {
+	reasonSummary := strings.ToLower(strings.TrimSpace(reason.Summary()))
+	if reasonSummary == "" {
+		return nil
+	}
+	keywords := limitedSupportKeywords(reasonSummary)
+	requiredMatches := min(2, len(keywords))
+
+	var related []*v1.LogEntry
+	for _, serviceLog := range serviceLogs {
+		summary := strings.ToLower(strings.TrimSpace(serviceLog.Summary()))
+		text := summary + "\n" + strings.ToLower(serviceLog.Description())
+		if strings.Contains(text, reasonSummary) || (summary != "" && strings.Contains(reasonSummary, summary)) {
+			related = append(related, serviceLog)
+			continue
+		}
+
+		matches := 0
+		for _, keyword := range keywords {
+			if strings.Contains(text, keyword) {
+				matches++
+			}
+		}
+		if requiredMatches > 0 && matches >= requiredMatches {
+			related = append(related, serviceLog)
+		}
+	}
+	return related
+}
+
+// printLimitedSupportServiceLogs annotates each limited support reason with the service logs that likely told the
+// customer about it
+func printLimitedSupportServiceLogs(reasons []*cmv1.LimitedSupportReason, serviceLogs []*v1.LogEntry) {
+	if len(reasons) == 0 {
+		return
+	}
+	fmt.Println(delimiter + "Service Logs related to Limited Support")
+
+	for _, reason := range reasons {
+		related := relatedServiceLogs(reason, serviceLogs)
+		fmt.Printf("%s:\n", reason.Summary())
+		if len(related) == 0 {
+			fmt.Println("  No matching service log found")
+			continue
+		}
+		for _, serviceLog := range related {
+			fmt.Printf("  - %s (%s)\n", serviceLog.Summary(), serviceLog.CreatedAt().Format(time.RFC3339))
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package cluster
+
+import (
+	"testing"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	v1 "github.com/openshift-online/ocm-sdk-go/servicelogs/v1"
+)
+
+func TestRelatedServiceLogs(t *testing.T) {
+	serviceLogs := []*v1.LogEntry{}
+	for _, summary := range []string{
+		"Action required: review cluster egress firewall",
+		"Cluster is in Limited Support due to unsupported cloud provider access removal",
+		"Cluster upgrade completed",
+		"Action required: restore deleted node security group",
+	} {
+		serviceLog, err := v1.NewLogEntry().Summary(summary).Build()
+		if err != nil {
+			t.Fatalf("failed to build service log: %v", err)
+		}
+		serviceLogs = append(serviceLogs, serviceLog)
+	}
+
+	tests := []struct {
+		reason   string
+		expected []string
+	}{
+		{
+			reason:   "Unsupported cloud provider access removal",
+			expected: []string{"Cluster is in Limited Support due to unsupported cloud provider access removal"},
+		},
+		{
+			reason:   "Node security group deleted",
+			expected: []string{"Action required: restore deleted node security group"},
+		},
+		{
+			reason:   "Egress blocked",
+			expected: nil,
+		},
+	}
+
+	for _, test := range tests {
+		reason, err := cmv1.NewLimitedSupportReason().Summary(test.reason).Build()
+		if err != nil {
+			t.Fatalf("failed to build limited support reason: %v", err)
+		}
+
+		related := relatedServiceLogs(reason, serviceLogs)
+		if len(related) != len(test.expected) {
+			t.Errorf("Test '%s' failed. Expected %d related service logs, got %d", test.reason, len(test.expected), len(related))
+			continue
+		}
+		for i, serviceLog := range related {
+			if serviceLog.Summary() != test.expected[i] {
+				t.Errorf("Test '%s' failed. Expected '%s', got '%s'", test.reason, test.expected[i], serviceLog.Summary())
+			}
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package cluster
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	v1 "github.com/openshift-online/ocm-sdk-go/servicelogs/v1"
+)
+
+// maxTrendBarWidth is the width of the bar of the busiest week in the service log trend
+const maxTrendBarWidth = 40
+
+// weeklyServiceLogCounts buckets the service logs into the number sent in each of the given number of weeks before
+// now, oldest week first
+func weeklyServiceLogCounts(serviceLogs []*v1.LogEntry, now time.Time, weeks int) []int {
+	counts := make([]int, weeks)
+	for _, serviceLog := range serviceLogs {
+		age := now.Sub(serviceLog.CreatedAt())
+		if age < 0 {
+			continue
+		}
+		weeksAgo := int(age / (7 * 24 * time.Hour))
+		if weeksAgo >= weeks {
+			continue
+		}
+		counts[weeks-1-weeksAgo]++
+	}
+	return counts
+}
+
+// printServiceLogTrend prints a bar chart of the weekly service log counts ending now
+func printServiceLogTrend(counts []int, now time.Time) {
+	fmt.Println(delimiter + fmt.Sprintf("Service Logs per week in the past %d weeks", len(counts)))
+
+	busiest := 0
+	for _, count := range counts {
+		busiest = max(busiest, count)
+	}
+
+	for i, count := range counts {
+		weekStart := now.AddDate(0, 0, -7*(len(counts)-i))
+		width := 0
+		if busiest > 0 {
+			width = count * maxTrendBarWidth / busiest
+		}
+		if count > 0 && width == 0 {
+			width = 1
+		}
+		fmt.Printf("%s  %-*s %d\n", weekStart.Format(time.DateOnly), maxTrendBarWidth, strings.Repeat("#", width), count)
+	}
+}
@@ -0,0 +1,28 @@
+package cluster
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	v1 "github.com/openshift-online/ocm-sdk-go/servicelogs/v1"
+)
+
+func TestWeeklyServiceLogCounts(t *testing.T) {
+	now := time.Date(2024, time.June, 30, 12, 0, 0, 0, time.UTC)
+	var serviceLogs []*v1.LogEntry
+	for _, daysAgo := range []int{0, 1, 6, 8, 20, 30, -1} {
+		serviceLog, err := v1.NewLogEntry().CreatedAt(now.AddDate(0, 0, -daysAgo)).Build()
+		if err != nil {
+			t.Fatalf("failed to build service log: %v", err)
+		}
+		serviceLogs = append(serviceLogs, serviceLog)
+	}
+
+	// 30 days ago is outside of the 4 weeks and the future one is ignored
+	expected := []int{0, 1, 1, 3}
+	actual := weeklyServiceLogCounts(serviceLogs, now, 4)
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected weekly counts %v, got %v", expected, actual)
+	}
+}
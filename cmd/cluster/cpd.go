@@ -64,11 +64,10 @@ func newCmdCpd() *cobra.Command {
 
 func (o *cpdOptions) run() error {
 	// Get the cluster info
-	ocmClient, err := utils.CreateConnection()
+	ocmClient, err := utils.SharedConnection()
 	if err != nil {
 		return err
 	}
-	defer ocmClient.Close()
 
 	cluster, err := utils.GetClusterAnyStatus(ocmClient, o.clusterID)
 	if err != nil {
@@ -56,11 +56,10 @@ func (o *detachStuckVolumeOptions) detachVolume(clusterID string) error {
 	if err != nil {
 		return err
 	}
-	connection, err := utils.CreateConnection()
+	connection, err := utils.SharedConnection()
 	if err != nil {
 		return err
 	}
-	defer connection.Close()
 	cluster, err := utils.GetCluster(connection, clusterID)
 	if err != nil {
 		return err
@@ -35,11 +35,10 @@ func FetchClusterDetails(clusterKey string) (hcpCluster HCPCluster, error error)
 	if err := ocmutils.IsValidClusterKey(clusterKey); err != nil {
 		return hcpCluster, err
 	}
-	connection, err := ocmutils.CreateConnection()
+	connection, err := ocmutils.SharedConnection()
 	if err != nil {
 		return HCPCluster{}, err
 	}
-	defer connection.Close()
 
 	cluster, err := ocmutils.GetCluster(connection, clusterKey)
 	if err != nil {
@@ -55,15 +55,10 @@ func getClusterNameFromInfraId(infraId string) (string, error) {
 }
 
 func (ops *fromInfraIdOptions) run(cmd *cobra.Command, args []string) error {
-	ocmClient, err := utils.CreateConnection()
+	ocmClient, err := utils.SharedConnection()
 	if err != nil {
 		return err
 	}
-	defer func() {
-		if errClose := ocmClient.Close(); errClose != nil {
-			fmt.Printf("cannot close the ocmClient (possible memory leak): %q", errClose)
-		}
-	}()
 
 	infraId := args[0]
 	clusterName, err := getClusterNameFromInfraId(infraId)
@@ -76,11 +76,10 @@ type ClusterHealthCondensedObject struct {
 
 func (o *healthOptions) run() error {
 
-	ocmClient, err := utils.CreateConnection()
+	ocmClient, err := utils.SharedConnection()
 	if err != nil {
 		return err
 	}
-	defer ocmClient.Close()
 
 	clusterResp, err := ocmClient.ClustersMgmt().V1().Clusters().Cluster(o.clusterID).Get().Send()
 	if err != nil {
@@ -321,7 +321,7 @@ func createGraphViz(ai *aggregateClusterInfo) map[graphviz.Node][]graphviz.Node
 }
 
 func (i *infoOptions) getClusters() (*infoClusters, error) {
-	ocmConnection, err := utils.CreateConnection()
+	ocmConnection, err := utils.SharedConnection()
 	if err != nil {
 		return nil, err
 	}
@@ -370,11 +370,10 @@ func (i *infoOptions) getClusters() (*infoClusters, error) {
 }
 
 func (i *infoOptions) getAWSSessions(clusters *infoClusters) (*hypershiftAWSClients, error) {
-	ocmClient, err := utils.CreateConnection()
+	ocmClient, err := utils.SharedConnection()
 	if err != nil {
 		return nil, err
 	}
-	defer ocmClient.Close()
 
 	customerConfig, err := osdCloud.CreateAWSV2Config(ocmClient, clusters.customerCluster)
 	// We have to overwrite the fact that backplane just mangled our configuration.
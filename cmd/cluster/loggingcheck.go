@@ -59,15 +59,10 @@ func (o *loggingCheckOptions) complete(cmd *cobra.Command, args []string) error
 
 	// Create an OCM client to talk to the cluster API
 	// the user has to be logged in (e.g. 'ocm login')
-	ocmClient, err := utils.CreateConnection()
+	ocmClient, err := utils.SharedConnection()
 	if err != nil {
 		return err
 	}
-	defer func() {
-		if err := ocmClient.Close(); err != nil {
-			fmt.Printf("Cannot close the ocmClient (possible memory leak): %q", err)
-		}
-	}()
 
 	clusters := utils.GetClusters(ocmClient, args)
 	if len(clusters) != 1 {
@@ -82,11 +77,10 @@ func (o *loggingCheckOptions) complete(cmd *cobra.Command, args []string) error
 
 func (o *loggingCheckOptions) run() error {
 
-	connection, err := utils.CreateConnection()
+	connection, err := utils.SharedConnection()
 	if err != nil {
 		return err
 	}
-	defer connection.Close()
 
 	// Get the client for the resource that manages the collection of clusters:
 	collection := connection.ClustersMgmt().V1().Clusters()
@@ -37,11 +37,10 @@ func (o *OrgId) Run(clusterID string) error {
 		return err
 	}
 
-	connection, err := ctlutil.CreateConnection()
+	connection, err := ctlutil.SharedConnection()
 	if err != nil {
 		return err
 	}
-	defer connection.Close()
 
 	org, err := ctlutil.GetOrganization(connection, clusterID)
 	if err != nil {
@@ -59,11 +59,10 @@ func (o *ownerOptions) complete(cmd *cobra.Command, _ []string) error {
 }
 
 func (o *ownerOptions) run() error {
-	connection, err := utils.CreateConnection()
+	connection, err := utils.SharedConnection()
 	if err != nil {
 		return err
 	}
-	defer connection.Close()
 
 	var (
 		accountName = o.userName
@@ -106,11 +106,10 @@ func (o *controlPlane) New() error {
 		return err
 	}
 
-	connection, err := utils.CreateConnection()
+	connection, err := utils.SharedConnection()
 	if err != nil {
 		return err
 	}
-	defer connection.Close()
 
 	cluster, err := utils.GetCluster(connection, o.clusterID)
 	if err != nil {
@@ -490,11 +489,10 @@ func (o *controlPlane) run() error {
 		return o.runWithCPMS(context.Background())
 	}
 
-	ocmClient, err := utils.CreateConnection()
+	ocmClient, err := utils.SharedConnection()
 	if err != nil {
 		return err
 	}
-	defer ocmClient.Close()
 
 	cfg, err := osdCloud.CreateAWSV2Config(ocmClient, o.cluster)
 	if err != nil {
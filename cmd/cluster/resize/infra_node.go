@@ -112,11 +112,10 @@ func (r *Infra) New() error {
 		return err
 	}
 
-	ocmClient, err := utils.CreateConnection()
+	ocmClient, err := utils.SharedConnection()
 	if err != nil {
 		return err
 	}
-	defer ocmClient.Close()
 	cluster, err := utils.GetClusterAnyStatus(ocmClient, r.clusterId)
 	if err != nil {
 		return fmt.Errorf("failed to get OCM cluster info for %s: %s", r.clusterId, err)
@@ -571,11 +570,10 @@ func (r *Infra) terminateCloudInstances(ctx context.Context, nodeList *corev1.No
 
 	switch r.cluster.CloudProvider().ID() {
 	case "aws":
-		ocmClient, err := utils.CreateConnection()
+		ocmClient, err := utils.SharedConnection()
 		if err != nil {
 			return err
 		}
-		defer ocmClient.Close()
 		cfg, err := osdCloud.CreateAWSV2Config(ocmClient, r.cluster)
 		if err != nil {
 			return err
@@ -67,11 +67,10 @@ func (r *Resync) New() error {
 		return err
 	}
 
-	ocmClient, err := utils.CreateConnection()
+	ocmClient, err := utils.SharedConnection()
 	if err != nil {
 		return err
 	}
-	defer ocmClient.Close()
 	cluster, err := utils.GetClusterAnyStatus(ocmClient, r.clusterId)
 	if err != nil {
 		return fmt.Errorf("failed to get OCM cluster info for %s: %v", r.clusterId, err)
@@ -93,7 +93,7 @@ Despite the logs from backplane, the ssh key is the only output channelled throu
 // PrintKey retrieves the cluster's private ssh key from hive and prints it to stdout.
 func PrintKey(identifier string, opts *clusterSSHKeyOpts) error {
 	// Login to the provided cluster's hive shard
-	ocmClient, err := utils.CreateConnection()
+	ocmClient, err := utils.SharedConnection()
 	if err != nil {
 		return fmt.Errorf("failed to establish connection to OCM: %w", err)
 	}
@@ -3,7 +3,6 @@ package support
 import (
 	"errors"
 	"fmt"
-	"os"
 
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	ctlutil "github.com/openshift/osdctl/pkg/utils"
@@ -18,16 +17,10 @@ func getLimitedSupportReasons(clusterId string) ([]*cmv1.LimitedSupportReason, e
 	}
 
 	//create connection to sdk
-	connection, err := ctlutil.CreateConnection()
+	connection, err := ctlutil.SharedConnection()
 	if err != nil {
 		return nil, err
 	}
-	defer func() {
-		if err := connection.Close(); err != nil {
-			fmt.Printf("Cannot close the connection: %q\n", err)
-			os.Exit(1)
-		}
-	}()
 
 	//getting the cluster
 	cluster, err := ctlutil.GetCluster(connection, clusterId)
@@ -4,7 +4,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
-	"os"
 
 	"github.com/openshift-online/ocm-cli/pkg/arguments"
 	sdk "github.com/openshift-online/ocm-sdk-go"
@@ -87,16 +86,10 @@ func (o *deleteOptions) run() error {
 	}
 
 	// Create an OCM client to talk to the cluster API
-	connection, err := ctlutil.CreateConnection()
+	connection, err := ctlutil.SharedConnection()
 	if err != nil {
 		return err
 	}
-	defer func() {
-		if err := connection.Close(); err != nil {
-			fmt.Printf("Cannot close the connection: %q\n", err)
-			os.Exit(1)
-		}
-	}()
 
 	// Stop here if dry-run
 	if o.isDryRun {
@@ -150,16 +150,10 @@ func (p *Post) Run(clusterID string) error {
 		return err
 	}
 
-	connection, err := ctlutil.CreateConnection()
+	connection, err := ctlutil.SharedConnection()
 	if err != nil {
 		return err
 	}
-	defer func() {
-		if err = connection.Close(); err != nil {
-			fmt.Printf("Cannot close the connection: %q\n", err)
-			os.Exit(1)
-		}
-	}()
 
 	p.cluster, err = ctlutil.GetCluster(connection, clusterID)
 	if err != nil {
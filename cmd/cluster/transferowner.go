@@ -442,15 +442,10 @@ func (o *transferOwnerOptions) run() error {
 
 	// Create an OCM client to talk to the cluster API
 	// the user has to be logged in (e.g. 'ocm login')
-	ocm, err := utils.CreateConnection()
+	ocm, err := utils.SharedConnection()
 	if err != nil {
 		return fmt.Errorf("failed to create OCM client: %w", err)
 	}
-	defer func() {
-		if ocmCloseErr := ocm.Close(); ocmCloseErr != nil {
-			fmt.Printf("Cannot close the ocm (possible memory leak): %q", ocmCloseErr)
-		}
-	}()
 
 	// Gather all required data
 	cluster, err := utils.GetClusterAnyStatus(ocm, o.clusterID)
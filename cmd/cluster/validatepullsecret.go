@@ -108,15 +108,10 @@ func getPullSecretElevated(clusterID string, reason string) (email string, err e
 // done means a service log has been sent
 func (o *validatePullSecretOptions) getPullSecretFromOCM() (string, error, bool) {
 	fmt.Println("Getting email from OCM")
-	ocm, err := utils.CreateConnection()
+	ocm, err := utils.SharedConnection()
 	if err != nil {
 		return "", err, false
 	}
-	defer func() {
-		if ocmCloseErr := ocm.Close(); ocmCloseErr != nil {
-			_, _ = fmt.Fprintf(os.Stderr, "Cannot close the ocm (possible memory leak): %q", ocmCloseErr)
-		}
-	}()
 
 	subscription, err := utils.GetSubscription(ocm, o.clusterID)
 	if err != nil {
@@ -11,6 +11,7 @@ import (
 	gcpv1alpha1 "github.com/openshift/gcp-project-operator/api/v1alpha1"
 	hivev1 "github.com/openshift/hive/apis/hive/v1"
 	hivev1alpha1 "github.com/openshift/hive/apis/hiveinternal/v1alpha1"
+	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
@@ -66,6 +67,16 @@ func NewCmdRoot(streams genericclioptions.IOStreams) *cobra.Command {
 				os.Exit(1)
 			}
 			viper.Set(aws.NoProxyFlag, noAwsProxy)
+			viper.Set(utils.OCMTimeoutFlag, globalOpts.OCMTimeout)
+			viper.Set(utils.TimingsFlag, globalOpts.Timings)
+
+			logLevel, err := log.ParseLevel(globalOpts.LogLevel)
+			if err != nil {
+				fmt.Printf("invalid --log-level: %v\n", err)
+				os.Exit(1)
+			}
+			log.SetLevel(logLevel)
+			log.SetOutput(streams.ErrOut)
 
 			skipVersionCheck, err := cmd.Flags().GetBool("skip-version-check")
 			if err != nil {
@@ -32,11 +32,10 @@ func newCmdList() *cobra.Command {
 }
 
 func (l *list) Run() error {
-	ocm, err := utils.CreateConnection()
+	ocm, err := utils.SharedConnection()
 	if err != nil {
 		return err
 	}
-	defer ocm.Close()
 
 	managementClusters, err := ocm.OSDFleetMgmt().V1().ManagementClusters().List().Send()
 	if err != nil {
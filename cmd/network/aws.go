@@ -33,11 +33,10 @@ func (e *EgressVerification) setupForAws(ctx context.Context) (*aws.Config, erro
 			return nil, errors.New("SRE has insufficient AWS permissions to run network verifier on hosted control plane clusters. Exiting")
 		}
 
-		ocmClient, err := utils.CreateConnection()
+		ocmClient, err := utils.SharedConnection()
 		if err != nil {
 			return nil, fmt.Errorf("error creating OCM connection: %v", err)
 		}
-		defer ocmClient.Close()
 
 		e.log.Info(ctx, "getting AWS credentials from backplane-api")
 		cfg, err := osdCloud.CreateAWSV2Config(ocmClient, e.cluster)
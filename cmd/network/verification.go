@@ -505,11 +505,10 @@ func (e *EgressVerification) defaultValidateEgressInput(ctx context.Context, pla
 
 func (e *EgressVerification) fetchCluster(ctx context.Context) error {
 	if e.ClusterId != "" {
-		ocmClient, err := utils.CreateConnection()
+		ocmClient, err := utils.SharedConnection()
 		if err != nil {
 			log.Fatalf("error creating OCM connection: %s", err)
 		}
-		defer ocmClient.Close()
 
 		cluster, err := utils.GetClusterAnyStatus(ocmClient, e.ClusterId)
 		if err != nil {
@@ -122,15 +122,10 @@ func SearchAllSubscriptionsByOrg(orgID string, status string, managedOnly bool)
 
 func getSubscriptions(orgID string, status string, managedOnly bool, page int, size int) (*accountsv1.SubscriptionsListResponse, error) {
 	// Create OCM client to talk
-	ocmClient, err := utils.CreateConnection()
+	ocmClient, err := utils.SharedConnection()
 	if err != nil {
 		return nil, err
 	}
-	defer func() {
-		if err := ocmClient.Close(); err != nil {
-			fmt.Printf("Cannot close the ocmClient (possible memory leak): %q", err)
-		}
-	}()
 
 	// Now get the matching orgs
 	response, err := createGetSubscriptionsRequest(ocmClient, orgID, status, managedOnly, page, size).Send()
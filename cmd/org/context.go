@@ -94,6 +94,7 @@ osdctl org context 1a2B3c4DefghIjkLMNOpQrSTUV5 -o json`,
 
 func init() {
 	contextCmd.Flags().StringP("output", "o", "", "output format for the results. only supported value currently is 'json'")
+	_ = contextCmd.RegisterFlagCompletionFunc("output", cobra.FixedCompletions([]string{"json"}, cobra.ShellCompDirectiveNoFileComp))
 }
 
 func printContextJson(clusterInfos []ClusterInfo) error {
@@ -190,11 +191,10 @@ func Context(orgId string) ([]ClusterInfo, error) {
 	}
 
 	// cluster info
-	ocmClient, err := utils.CreateConnection()
+	ocmClient, err := utils.SharedConnection()
 	if err != nil {
 		return nil, fmt.Errorf("failed to create OCM client: %w", err)
 	}
-	defer ocmClient.Close()
 
 	var orgClustersInfo []ClusterInfo
 
@@ -49,15 +49,10 @@ func run(cmd *cobra.Command) error {
 
 func getCurrentOrg() (*sdk.Response, error) {
 	// Create OCM client to talk
-	ocmClient, err := utils.CreateConnection()
+	ocmClient, err := utils.SharedConnection()
 	if err != nil {
 		return nil, err
 	}
-	defer func() {
-		if err := ocmClient.Close(); err != nil {
-			fmt.Printf("Cannot close the ocmClient (possible memory leak): %q", err)
-		}
-	}()
 
 	// Now get the current org
 	return sendRequest(createGetCurrentOrgRequest(ocmClient))
@@ -56,15 +56,10 @@ func getCustomers(cmd *cobra.Command) error {
 	pageIndex := 1
 
 	// Create OCM client to talk
-	ocmClient, err := utils.CreateConnection()
+	ocmClient, err := utils.SharedConnection()
 	if err != nil {
 		return err
 	}
-	defer func() {
-		if err := ocmClient.Close(); err != nil {
-			fmt.Printf("Cannot close the ocmClient (possible memory leak): %q", err)
-		}
-	}()
 
 	if !paying {
 		subsType = "Config"
@@ -48,15 +48,10 @@ func describeOrg(cmd *cobra.Command, orgID string) error {
 
 func sendDescribeOrgRequest(orgID string) (*sdk.Response, error) {
 	// Create OCM client to talk
-	ocmClient, err := utils.CreateConnection()
+	ocmClient, err := utils.SharedConnection()
 	if err != nil {
 		return nil, err
 	}
-	defer func() {
-		if err := ocmClient.Close(); err != nil {
-			fmt.Printf("Cannot close the ocmClient (possible memory leak): %q", err)
-		}
-	}()
 
 	// Now get the matching orgs
 	return sendRequest(createDescribeRequest(ocmClient, orgID))
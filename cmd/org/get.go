@@ -113,15 +113,10 @@ func searchOrgs(cmd *cobra.Command) error {
 
 func getOrgs() (*sdk.Response, error) {
 	// Create OCM client to talk
-	ocmClient, err := utils.CreateConnection()
+	ocmClient, err := utils.SharedConnection()
 	if err != nil {
 		return nil, err
 	}
-	defer func() {
-		if err := ocmClient.Close(); err != nil {
-			fmt.Printf("Cannot close the ocmClient (possible memory leak): %q", err)
-		}
-	}()
 	request := ocmClient.Get()
 	apiPath := ""
 	switch getSearchType() {
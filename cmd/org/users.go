@@ -75,11 +75,10 @@ func getUsers(orgID string) error {
 	searchQuery := ""
 
 	searchQuery = fmt.Sprintf("organization_id='%s'", orgID)
-	ocmClient, err := utils.CreateConnection()
+	ocmClient, err := utils.SharedConnection()
 	if err != nil {
 		return err
 	}
-	defer ocmClient.Close()
 
 	var userList []*userModel
 	for {
@@ -3,6 +3,7 @@ package promote
 import (
 	"fmt"
 
+	"github.com/openshift/osdctl/cmd/promote/git"
 	"github.com/openshift/osdctl/cmd/promote/pko"
 	"github.com/openshift/osdctl/cmd/promote/saas"
 	"github.com/spf13/cobra"
@@ -17,8 +18,11 @@ func NewCmdPromote() *cobra.Command {
 		DisableAutoGenTag: true,
 	}
 
+	promoteCmd.PersistentFlags().Bool(git.DryRunFlag, false, "Print the promotion without creating a branch, writing files or committing in app-interface")
+
 	promoteCmd.AddCommand(saas.NewCmdSaas())
 	promoteCmd.AddCommand(pko.NewCmdPKO())
+	promoteCmd.AddCommand(saas.NewCmdObservability())
 
 	return promoteCmd
 }
@@ -1,13 +1,16 @@
 package git
 
 import (
+	"errors"
 	"fmt"
-	"log"
+	"net/url"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"regexp"
 	"strings"
 
+	log "github.com/sirupsen/logrus"
 	"gopkg.in/yaml.v3"
 )
 
@@ -24,8 +27,25 @@ type Service struct {
 	} `yaml:"resourceTemplates"`
 }
 
+// AppInterfacePathEnv is the environment variable pointing to the app-interface checkout when --appInterfaceDir is not set
+const AppInterfacePathEnv = "APP_INTERFACE_PATH"
+
+// DryRunFlag is the persistent promote flag that prevents any change to the app-interface checkout
+const DryRunFlag = "dry-run"
+
+// ErrDryRun is returned by the AppInterface methods modifying the checkout when DryRun is set
+var ErrDryRun = errors.New("dry run, app-interface was not modified")
+
+// ErrInvalidSaasFile is returned when a SAAS file can't be parsed
+var ErrInvalidSaasFile = errors.New("invalid SAAS file")
+
+var commitAuthorRegex = regexp.MustCompile(`^[^<>]+ <[^<>\s]+@[^<>\s]+>$`)
+
 type AppInterface struct {
 	GitDirectory string
+	RemoteURL    string
+	// DryRun makes every method modifying the checkout return ErrDryRun instead
+	DryRun bool
 }
 
 func DefaultAppInterfaceDirectory() string {
@@ -33,125 +53,234 @@ func DefaultAppInterfaceDirectory() string {
 }
 
 func BootstrapOsdCtlForAppInterfaceAndServicePromotions(appInterfaceCheckoutDir string) AppInterface {
+	a, err := FindAppInterface(appInterfaceCheckoutDir)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if appInterfaceCheckoutDir == "" && a.GitDirectory == DefaultAppInterfaceDirectory() {
+		log.Infof("Found AppInterface in %s.", a.GitDirectory)
+	}
+	return a
+}
+
+// FindAppInterface locates the app-interface checkout. The directory is resolved in the following order:
+//  1. the provided directory (--appInterfaceDir)
+//  2. the APP_INTERFACE_PATH environment variable
+//  3. the git repository of the current directory
+//  4. DefaultAppInterfaceDirectory
+func FindAppInterface(appInterfaceCheckoutDir string) (AppInterface, error) {
 	a := AppInterface{}
 	if appInterfaceCheckoutDir != "" {
 		a.GitDirectory = appInterfaceCheckoutDir
-		err := checkAppInterfaceCheckout(a.GitDirectory)
+		remoteURL, err := checkAppInterfaceCheckout(a.GitDirectory)
 		if err != nil {
-			log.Fatalf("Provided directory %s is not an AppInterface directory: %v", a.GitDirectory, err)
+			return a, fmt.Errorf("provided directory %s is not an AppInterface directory: %v", a.GitDirectory, err)
 		}
-		return a
+		a.RemoteURL = remoteURL
+		return a, nil
+	}
+
+	if envDir := os.Getenv(AppInterfacePathEnv); envDir != "" {
+		a.GitDirectory = envDir
+		remoteURL, err := checkAppInterfaceCheckout(a.GitDirectory)
+		if err != nil {
+			return a, fmt.Errorf("%s=%s is not an AppInterface directory: %v", AppInterfacePathEnv, a.GitDirectory, err)
+		}
+		a.RemoteURL = remoteURL
+		return a, nil
 	}
 
 	dir, err := getBaseDir()
 	if err == nil {
 		a.GitDirectory = dir
-		err = checkAppInterfaceCheckout(a.GitDirectory)
+		var remoteURL string
+		remoteURL, err = checkAppInterfaceCheckout(a.GitDirectory)
 		if err == nil {
-			return a
+			a.RemoteURL = remoteURL
+			return a, nil
 		}
 	}
 
-	log.Printf("Not running in AppInterface directory: %v - Trying %s next\n", err, DefaultAppInterfaceDirectory())
 	a.GitDirectory = DefaultAppInterfaceDirectory()
-	err = checkAppInterfaceCheckout(a.GitDirectory)
-	if err != nil {
-		log.Fatalf("%s is not an AppInterface directory: %v", DefaultAppInterfaceDirectory(), err)
+	remoteURL, defaultErr := checkAppInterfaceCheckout(a.GitDirectory)
+	if defaultErr != nil {
+		return a, fmt.Errorf("not running in AppInterface directory: %v - and %s is not an AppInterface directory: %v", err, DefaultAppInterfaceDirectory(), defaultErr)
 	}
+	a.RemoteURL = remoteURL
 
-	log.Printf("Found AppInterface in %s.\n", a.GitDirectory)
-	return a
+	return a, nil
 }
 
-// checkAppInterfaceCheckout checks if the script is running in the checkout of app-interface
-func checkAppInterfaceCheckout(directory string) error {
+// checkAppInterfaceCheckout checks if the script is running in the checkout of app-interface, returning the URL of its push remote
+func checkAppInterfaceCheckout(directory string) (string, error) {
+	log.Debugf("Checking %s is an app-interface checkout", directory)
 	cmd := exec.Command("git", "remote", "-v")
 	cmd.Dir = directory
 	output, err := cmd.CombinedOutput()
 	if err != nil {
-		return fmt.Errorf("error executing 'git remote -v': %v", err)
+		return "", fmt.Errorf("error executing 'git remote -v': %v", err)
 	}
 
 	outputString := string(output)
 
 	// Check if the output contains the app-interface repository URL
 	if !strings.Contains(outputString, "gitlab.cee.redhat.com") && !strings.Contains(outputString, "app-interface") {
-		return fmt.Errorf("not running in checkout of app-interface")
+		return "", fmt.Errorf("not running in checkout of app-interface")
 	}
 	//fmt.Println("Running in checkout of app-interface.")
 
+	return originRemoteURL(outputString), nil
+}
+
+// originRemoteURL returns the URL of the 'origin' remote from the output of 'git remote -v', falling back to the first remote listed
+func originRemoteURL(remotes string) string {
+	var firstURL string
+	for _, line := range strings.Split(remotes, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		if firstURL == "" {
+			firstURL = fields[1]
+		}
+		if fields[0] == "origin" {
+			return fields[1]
+		}
+	}
+	return firstURL
+}
+
+// MergeRequestURL returns the GitLab URL to open a merge request for the given branch of the app-interface checkout
+func (a AppInterface) MergeRequestURL(branchName string) string {
+	if a.RemoteURL == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s/-/merge_requests/new?merge_request[source_branch]=%s", RepoWebURL(a.RemoteURL), url.QueryEscape(branchName))
+}
+
+// TargetEnvironment returns the name of the environment a namespace belongs to, read from the namespace file
+// referenced by a SAAS target. An empty string is returned if it can't be determined
+func (a AppInterface) TargetEnvironment(namespaceRef string) string {
+	namespaceData, err := os.ReadFile(filepath.Join(a.GitDirectory, "data", namespaceRef))
+	if err != nil {
+		return ""
+	}
+
+	var namespace struct {
+		Environment map[string]string `yaml:"environment"`
+	}
+	err = yaml.Unmarshal(namespaceData, &namespace)
+	if err != nil {
+		return ""
+	}
+
+	environment := namespace.Environment["$ref"]
+	if environment == "" {
+		return ""
+	}
+	return strings.TrimSuffix(filepath.Base(environment), filepath.Ext(environment))
+}
+
+// RemoteBranchExists reports whether the branch exists on the origin remote of the app-interface checkout, meaning a
+// promotion using it is already in flight
+func (a AppInterface) RemoteBranchExists(branchName string) (bool, error) {
+	log.Debugf("Checking whether branch %s exists on the origin remote of %s", branchName, a.GitDirectory)
+	cmd := exec.Command("git", "ls-remote", "--exit-code", "--heads", "origin", "refs/heads/"+branchName)
+	cmd.Dir = a.GitDirectory
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+	if err == nil {
+		return true, nil
+	}
+
+	// ls-remote exits with 2 when no matching ref is found
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == 2 {
+		return false, nil
+	}
+	return false, fmt.Errorf("failed to list the branches of the origin remote: %v\n%s", err, strings.TrimSpace(stderr.String()))
+}
+
+// CheckPromotionBranch refuses a promotion whose branch already exists on the origin remote, as it would conflict with
+// the merge request already in flight, unless forced. Failing to list the remote branches only logs a warning
+func (a AppInterface) CheckPromotionBranch(branchName string, force bool) error {
+	exists, err := a.RemoteBranchExists(branchName)
+	if err != nil {
+		log.Warnf("Unable to check whether branch %s already exists on the origin remote: %v", branchName, err)
+		return nil
+	}
+	if !exists {
+		return nil
+	}
+	if !force {
+		return fmt.Errorf("branch %s already exists on the origin remote, this promotion is likely already in flight. Pass --force to promote anyway", branchName)
+	}
+	log.Warnf("Branch %s already exists on the origin remote, promoting anyway", branchName)
+	return nil
+}
+
+// FileAtCommit returns the content of a file of the app-interface checkout as of the given commit
+func (a AppInterface) FileAtCommit(commit, file string) ([]byte, error) {
+	relativePath, err := filepath.Rel(a.GitDirectory, file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find %s in app-interface: %v", file, err)
+	}
+
+	log.Debugf("Reading %s at app-interface commit %s", relativePath, commit)
+	cmd := exec.Command("git", "show", fmt.Sprintf("%s:%s", commit, filepath.ToSlash(relativePath)))
+	cmd.Dir = a.GitDirectory
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s at app-interface commit %s: %v\n%s", relativePath, commit, err, strings.TrimSpace(stderr.String()))
+	}
+	return output, nil
+}
+
+// CheckWorkingTreeClean returns an error listing the uncommitted files if the app-interface checkout is dirty
+func (a AppInterface) CheckWorkingTreeClean() error {
+	log.Debugf("Checking the app-interface checkout %s has no uncommitted changes", a.GitDirectory)
+	cmd := exec.Command("git", "status", "--porcelain")
+	cmd.Dir = a.GitDirectory
+	output, err := cmd.Output()
+	if err != nil {
+		return fmt.Errorf("failed to get status of app-interface checkout: %v", err)
+	}
+
+	var dirtyFiles []string
+	for _, line := range strings.Split(strings.TrimRight(string(output), "\n"), "\n") {
+		if strings.TrimSpace(line) != "" {
+			dirtyFiles = append(dirtyFiles, "\t"+line)
+		}
+	}
+
+	if len(dirtyFiles) > 0 {
+		return fmt.Errorf("app-interface checkout %s has uncommitted changes:\n%s", a.GitDirectory, strings.Join(dirtyFiles, "\n"))
+	}
+
 	return nil
 }
 
-func GetCurrentGitHashFromAppInterface(saarYamlFile []byte, serviceName string, namespaceRef string) (string, string, error) {
-	var currentGitHash string
+// GetCurrentGitHashFromAppInterface returns every target matching namespacePattern along with the service repo URL.
+// A nil namespacePattern selects the targets matching DefaultProductionNamespacePattern
+func GetCurrentGitHashFromAppInterface(saarYamlFile []byte, serviceName string, namespacePattern *regexp.Regexp) ([]SaasTarget, string, error) {
 	var serviceRepo string
 	var service Service
 	err := yaml.Unmarshal(saarYamlFile, &service)
 	if err != nil {
-		log.Fatal(fmt.Errorf("cannot unmarshal yaml data of service %s: %v", serviceName, err))
+		return nil, "", fmt.Errorf("%w: cannot unmarshal yaml data of service %s: %v", ErrInvalidSaasFile, serviceName, err)
 	}
 
-	if namespaceRef != "" {
-		for _, resourceTemplate := range service.ResourceTemplates {
-			for _, target := range resourceTemplate.Targets {
-				if strings.Contains(target.Namespace["$ref"], namespaceRef) {
-					currentGitHash = target.Ref
-					break
-				}
-			}
-		}
-	} else if service.Name == "saas-configuration-anomaly-detection-db" {
-		for _, resourceTemplate := range service.ResourceTemplates {
-			for _, target := range resourceTemplate.Targets {
-				if strings.Contains(target.Namespace["$ref"], "app-sre-observability-production-int.yml") {
-					currentGitHash = target.Ref
-					break
-				}
-			}
-		}
-	} else if strings.Contains(service.Name, "configuration-anomaly-detection") {
-		for _, resourceTemplate := range service.ResourceTemplates {
-			for _, target := range resourceTemplate.Targets {
-				if strings.Contains(target.Namespace["$ref"], "configuration-anomaly-detection-production") {
-					currentGitHash = target.Ref
-					break
-				}
-			}
-		}
-	} else if strings.Contains(service.Name, "rhobs-rules-and-dashboards") {
-		for _, resourceTemplate := range service.ResourceTemplates {
-			for _, target := range resourceTemplate.Targets {
-				if strings.Contains(service.Name, "production") {
-					currentGitHash = target.Ref
-					break
-				}
-			}
-		}
-	} else if strings.Contains(service.Name, "saas-backplane-api") {
-		for _, resourceTemplate := range service.ResourceTemplates {
-			for _, target := range resourceTemplate.Targets {
-				if strings.Contains(target.Namespace["$ref"], "backplanep") {
-					currentGitHash = target.Ref
-					break
-				}
-			}
-		}
-	} else {
-		for _, resourceTemplate := range service.ResourceTemplates {
-			if !strings.Contains(resourceTemplate.Name, "package") {
-				for _, target := range resourceTemplate.Targets {
-					if strings.Contains(target.Namespace["$ref"], "hivep") {
-						currentGitHash = target.Ref
-						break
-					}
-				}
-			}
-		}
+	if namespacePattern == nil {
+		namespacePattern = regexp.MustCompile(DefaultProductionNamespacePattern)
 	}
-
-	if currentGitHash == "" {
-		return "", "", fmt.Errorf("production namespace not found for service %s", serviceName)
+	targets := findProductionTargets(service, namespacePattern)
+	if len(targets) == 0 {
+		return nil, "", fmt.Errorf("production namespace not found for service %s", serviceName)
 	}
 
 	if len(service.ResourceTemplates) > 0 {
@@ -159,10 +288,10 @@ func GetCurrentGitHashFromAppInterface(saarYamlFile []byte, serviceName string,
 	}
 
 	if serviceRepo == "" {
-		return "", "", fmt.Errorf("service repo not found for service %s", serviceName)
+		return nil, "", fmt.Errorf("service repo not found for service %s", serviceName)
 	}
 
-	return currentGitHash, serviceRepo, nil
+	return targets, serviceRepo, nil
 }
 
 func GetCurrentPackageTagFromAppInterface(saasFile string) (string, error) {
@@ -171,6 +300,11 @@ func GetCurrentPackageTagFromAppInterface(saasFile string) (string, error) {
 		return "", fmt.Errorf("failed to read file '%s': %w", saasFile, err)
 	}
 
+	err = ValidateSaasFile(saasFile, saasData)
+	if err != nil {
+		return "", err
+	}
+
 	service := Service{}
 	err = yaml.Unmarshal(saasData, &service)
 	if err != nil {
@@ -188,7 +322,7 @@ func GetCurrentPackageTagFromAppInterface(saasFile string) (string, error) {
 		if strings.Contains(resourceTemplate.Name, "package") {
 			for _, target := range resourceTemplate.Targets {
 				if strings.Contains(target.Namespace["$ref"], "hivep") {
-					currentPackageTag = target.Parameters["PACKAGE_TAG"].(string)
+					currentPackageTag, _ = target.Parameters["PACKAGE_TAG"].(string)
 				}
 			}
 		}
@@ -196,7 +330,42 @@ func GetCurrentPackageTagFromAppInterface(saasFile string) (string, error) {
 	return currentPackageTag, nil
 }
 
-func (a AppInterface) UpdateAppInterface(serviceName, saasFile, currentGitHash, promotionGitHash, branchName string) error {
+// GetPackageRepoFromAppInterface returns the URL of the repository the package resource template of the SAAS file
+// is built from
+func GetPackageRepoFromAppInterface(saasFile string) (string, error) {
+	saasData, err := os.ReadFile(saasFile)
+	if err != nil {
+		return "", fmt.Errorf("failed to read file '%s': %w", saasFile, err)
+	}
+
+	service := Service{}
+	err = yaml.Unmarshal(saasData, &service)
+	if err != nil {
+		return "", fmt.Errorf("failed to unmarshal service definition: %w", err)
+	}
+
+	for _, resourceTemplate := range service.ResourceTemplates {
+		if strings.Contains(resourceTemplate.Name, "package") && resourceTemplate.URL != "" {
+			return resourceTemplate.URL, nil
+		}
+	}
+	return "", fmt.Errorf("no package resource template with a url found in %s", saasFile)
+}
+
+// checkWritable guards every method creating branches, writing files or committing in the checkout
+func (a AppInterface) checkWritable() error {
+	if a.DryRun {
+		return ErrDryRun
+	}
+	return nil
+}
+
+// UpdateAppInterface creates the promotion branch and updates the ref of each of the given targets to promotionGitHash
+func (a AppInterface) UpdateAppInterface(saasFile string, targets []SaasTarget, promotionGitHash, branchName string) error {
+	if err := a.checkWritable(); err != nil {
+		return err
+	}
+	log.Debugf("Creating branch %s from master in %s", branchName, a.GitDirectory)
 	cmd := exec.Command("git", "checkout", "master")
 	cmd.Dir = a.GitDirectory
 	err := cmd.Run()
@@ -208,7 +377,7 @@ func (a AppInterface) UpdateAppInterface(serviceName, saasFile, currentGitHash,
 	cmd.Dir = a.GitDirectory
 	err = cmd.Run()
 	if err != nil {
-		fmt.Printf("failed to cleanup branch %s: %v, continuing to create it.\n", branchName, err)
+		log.Debugf("Failed to cleanup branch %s: %v, continuing to create it", branchName, err)
 	}
 
 	cmd = exec.Command("git", "checkout", "-b", branchName, "master")
@@ -224,10 +393,12 @@ func (a AppInterface) UpdateAppInterface(serviceName, saasFile, currentGitHash,
 		return fmt.Errorf("failed to read file %s: %v", saasFile, err)
 	}
 
-	// Replace the hash in the file content
-	newContent := strings.ReplaceAll(string(fileContent), currentGitHash, promotionGitHash)
+	newContent, err := updateTargetRefs(fileContent, targets, promotionGitHash)
+	if err != nil {
+		return fmt.Errorf("failed to update refs in file %s: %v", saasFile, err)
+	}
 
-	err = os.WriteFile(saasFile, []byte(newContent), 0644)
+	err = os.WriteFile(saasFile, newContent, 0644)
 	if err != nil {
 		return fmt.Errorf("failed to write to file %s: %v", saasFile, err)
 	}
@@ -236,6 +407,10 @@ func (a AppInterface) UpdateAppInterface(serviceName, saasFile, currentGitHash,
 }
 
 func (a AppInterface) UpdatePackageTag(saasFile, oldTag, promotionTag, branchName string) error {
+	if err := a.checkWritable(); err != nil {
+		return err
+	}
+	log.Debugf("Creating branch %s from master in %s", branchName, a.GitDirectory)
 	cmd := exec.Command("git", "checkout", "master")
 	cmd.Dir = a.GitDirectory
 	err := cmd.Run()
@@ -247,7 +422,14 @@ func (a AppInterface) UpdatePackageTag(saasFile, oldTag, promotionTag, branchNam
 	cmd.Dir = a.GitDirectory
 	err = cmd.Run()
 	if err != nil {
-		fmt.Printf("failed to cleanup branch %s: %v, continuing to create it.\n", branchName, err)
+		log.Debugf("Failed to cleanup branch %s: %v, continuing to create it", branchName, err)
+	}
+
+	cmd = exec.Command("git", "checkout", "-b", branchName, "master")
+	cmd.Dir = a.GitDirectory
+	err = cmd.Run()
+	if err != nil {
+		return fmt.Errorf("failed to create branch %s: %v, does it already exist? If so, please delete it with `git branch -D %s` first", branchName, err, branchName)
 	}
 
 	// Update the hash in the SAAS file
@@ -266,22 +448,53 @@ func (a AppInterface) UpdatePackageTag(saasFile, oldTag, promotionTag, branchNam
 	return nil
 }
 
-func (a AppInterface) CommitSaasFile(saasFile, commitMessage string) error {
+// ValidateCommitAuthor ensures an author override is in the 'Name <email>' form expected by git
+func ValidateCommitAuthor(author string) error {
+	if author == "" {
+		return nil
+	}
+	if !commitAuthorRegex.MatchString(author) {
+		return fmt.Errorf("invalid commit author '%s', expected the form 'Name <email>'", author)
+	}
+	return nil
+}
+
+// CommitSaasFile commits the given SAAS file and returns the SHA of the new commit. The author defaults to the git
+// config of the checkout when empty. With signoff a Signed-off-by trailer of the configured git identity is added
+func (a AppInterface) CommitSaasFile(saasFile, commitMessage, author string, signoff bool) (string, error) {
+	if err := a.checkWritable(); err != nil {
+		return "", err
+	}
+	log.Debugf("Committing %s in %s", saasFile, a.GitDirectory)
 	// Commit the change
 	cmd := exec.Command("git", "add", saasFile)
 	cmd.Dir = a.GitDirectory
 	err := cmd.Run()
 	if err != nil {
-		return fmt.Errorf("failed to add file %s: %v", saasFile, err)
+		return "", fmt.Errorf("failed to add file %s: %v", saasFile, err)
 	}
 
 	//commitMessage := fmt.Sprintf("Promote %s to %s", serviceName, promotionGitHash)
-	cmd = exec.Command("git", "commit", "-m", commitMessage)
+	commitArgs := []string{"commit", "-m", commitMessage}
+	if author != "" {
+		commitArgs = append(commitArgs, "--author", author)
+	}
+	if signoff {
+		commitArgs = append(commitArgs, "--signoff")
+	}
+	cmd = exec.Command("git", commitArgs...)
 	cmd.Dir = a.GitDirectory
 	err = cmd.Run()
 	if err != nil {
-		return fmt.Errorf("failed to commit changes: %v", err)
+		return "", fmt.Errorf("failed to commit changes: %v", err)
 	}
 
-	return nil
+	cmd = exec.Command("git", "rev-parse", "HEAD")
+	cmd.Dir = a.GitDirectory
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to get the commit SHA: %v", err)
+	}
+
+	return strings.TrimSpace(string(output)), nil
 }
@@ -0,0 +1,234 @@
+package git
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/openshift/osdctl/cmd/promote/git/testutil"
+)
+
+func TestMergeRequestURL(t *testing.T) {
+	remotes := "upstream\tgit@gitlab.cee.redhat.com:service/app-interface.git (fetch)\n" +
+		"upstream\tgit@gitlab.cee.redhat.com:service/app-interface.git (push)\n" +
+		"origin\tgit@gitlab.cee.redhat.com:jdoe/app-interface.git (fetch)\n" +
+		"origin\tgit@gitlab.cee.redhat.com:jdoe/app-interface.git (push)\n"
+
+	a := AppInterface{RemoteURL: originRemoteURL(remotes)}
+	expected := "https://gitlab.cee.redhat.com/jdoe/app-interface/-/merge_requests/new?merge_request[source_branch]=promote-saas-example-abc123"
+	actual := a.MergeRequestURL("promote-saas-example-abc123")
+	if actual != expected {
+		t.Errorf("expected merge request URL %s, got %s", expected, actual)
+	}
+
+	if url := (AppInterface{}).MergeRequestURL("promote-saas-example-abc123"); url != "" {
+		t.Errorf("expected no merge request URL without a remote, got %s", url)
+	}
+}
+
+// newTestAppInterface creates a git repository with an app-interface origin remote
+func newTestAppInterface(t *testing.T) string {
+	t.Helper()
+	dir, _ := testutil.NewServiceRepo(t, 0)
+	cmd := exec.Command("git", "remote", "add", "origin", "git@gitlab.cee.redhat.com:service/app-interface.git")
+	cmd.Dir = dir
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("failed to add remote: %v\n%s", err, output)
+	}
+	return dir
+}
+
+func TestRemoteBranchExists(t *testing.T) {
+	remote, _ := testutil.NewServiceRepo(t, 1)
+	cmd := exec.Command("git", "branch", "promote-example-abc")
+	cmd.Dir = remote
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("failed to create branch: %v\n%s", err, output)
+	}
+
+	a := AppInterface{GitDirectory: newTestAppInterface(t)}
+	cmd = exec.Command("git", "remote", "set-url", "origin", remote)
+	cmd.Dir = a.GitDirectory
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("failed to set remote: %v\n%s", err, output)
+	}
+
+	tests := []struct {
+		branch   string
+		expected bool
+	}{
+		{branch: "promote-example-abc", expected: true},
+		{branch: "promote-example-def", expected: false},
+	}
+	for _, test := range tests {
+		exists, err := a.RemoteBranchExists(test.branch)
+		if err != nil {
+			t.Fatalf("Test '%s' failed. Unexpected error: %v", test.branch, err)
+		}
+		if exists != test.expected {
+			t.Errorf("Test '%s' failed. Expected %t, got %t", test.branch, test.expected, exists)
+		}
+	}
+}
+
+func TestFindAppInterface(t *testing.T) {
+	flagDir := newTestAppInterface(t)
+	envDir := newTestAppInterface(t)
+	t.Setenv(AppInterfacePathEnv, envDir)
+
+	a, err := FindAppInterface(flagDir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if a.GitDirectory != flagDir {
+		t.Errorf("expected the provided directory %s to take precedence, got %s", flagDir, a.GitDirectory)
+	}
+
+	a, err = FindAppInterface("")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if a.GitDirectory != envDir {
+		t.Errorf("expected %s from %s, got %s", envDir, AppInterfacePathEnv, a.GitDirectory)
+	}
+
+	t.Setenv(AppInterfacePathEnv, t.TempDir())
+	if _, err = FindAppInterface(""); err == nil {
+		t.Errorf("expected an error when %s is not an app-interface checkout", AppInterfacePathEnv)
+	}
+}
+
+func TestCommitSaasFile(t *testing.T) {
+	t.Setenv("GIT_COMMITTER_NAME", "test")
+	t.Setenv("GIT_COMMITTER_EMAIL", "test@example.com")
+	a := AppInterface{GitDirectory: newTestAppInterface(t)}
+	err := os.WriteFile(filepath.Join(a.GitDirectory, "saas-example.yaml"), []byte(testSaasFile), 0600)
+	if err != nil {
+		t.Fatalf("failed to write SAAS file: %v", err)
+	}
+
+	sha, err := a.CommitSaasFile("saas-example.yaml", "Promote example", "Jane Doe <jdoe@example.com>", true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cmd := exec.Command("git", "log", "-1", "--format=%H %an %(trailers:key=Signed-off-by,valueonly)")
+	cmd.Dir = a.GitDirectory
+	output, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("git log failed: %v", err)
+	}
+	expected := sha + " Jane Doe test <test@example.com>"
+	if actual := strings.TrimSpace(string(output)); actual != expected {
+		t.Errorf("expected the latest commit to be '%s', got '%s'", expected, actual)
+	}
+}
+
+func TestGetPackageRepoFromAppInterface(t *testing.T) {
+	repo, err := GetPackageRepoFromAppInterface("testdata/saas-multi-target.yaml")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if repo != "https://github.com/openshift/example-operator" {
+		t.Errorf("expected the package repo https://github.com/openshift/example-operator, got %s", repo)
+	}
+}
+
+func TestFileAtCommit(t *testing.T) {
+	t.Setenv("GIT_COMMITTER_NAME", "test")
+	t.Setenv("GIT_COMMITTER_EMAIL", "test@example.com")
+	a := AppInterface{GitDirectory: newTestAppInterface(t)}
+	saasFile := filepath.Join(a.GitDirectory, "saas-example.yaml")
+
+	var commits []string
+	for _, content := range []string{"ref: abc\n", "ref: def\n"} {
+		err := os.WriteFile(saasFile, []byte(content), 0600)
+		if err != nil {
+			t.Fatalf("failed to write SAAS file: %v", err)
+		}
+		sha, err := a.CommitSaasFile(saasFile, "Promote example", "test <test@example.com>", false)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		commits = append(commits, sha)
+	}
+
+	content, err := a.FileAtCommit(commits[0], saasFile)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(content) != "ref: abc\n" {
+		t.Errorf("expected the content of the first commit, got '%s'", content)
+	}
+
+	if _, err := a.FileAtCommit("0000000", saasFile); err == nil {
+		t.Errorf("expected an error for an unknown commit")
+	}
+}
+
+func TestDryRunLeavesCheckoutUntouched(t *testing.T) {
+	a := AppInterface{GitDirectory: newTestAppInterface(t), DryRun: true}
+	saasFile := filepath.Join(a.GitDirectory, "saas-example.yaml")
+	err := os.WriteFile(saasFile, []byte(testSaasFile), 0600)
+	if err != nil {
+		t.Fatalf("failed to write SAAS file: %v", err)
+	}
+
+	if err := a.UpdatePackageTag(saasFile, "abc", "def", "promote-example"); !errors.Is(err, ErrDryRun) {
+		t.Errorf("expected UpdatePackageTag to return ErrDryRun, got %v", err)
+	}
+	if err := a.UpdateAppInterface(saasFile, nil, "def", "promote-example"); !errors.Is(err, ErrDryRun) {
+		t.Errorf("expected UpdateAppInterface to return ErrDryRun, got %v", err)
+	}
+	if _, err := a.CommitSaasFile(saasFile, "Promote example", "", false); !errors.Is(err, ErrDryRun) {
+		t.Errorf("expected CommitSaasFile to return ErrDryRun, got %v", err)
+	}
+
+	content, err := os.ReadFile(saasFile)
+	if err != nil {
+		t.Fatalf("failed to read SAAS file: %v", err)
+	}
+	if string(content) != testSaasFile {
+		t.Errorf("expected the SAAS file to be unchanged")
+	}
+}
+
+func TestUpdatePackageTagCreatesBranch(t *testing.T) {
+	dir, _ := testutil.NewServiceRepo(t, 1)
+	cmd := exec.Command("git", "branch", "-M", "master")
+	cmd.Dir = dir
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("failed to rename branch: %v\n%s", err, output)
+	}
+	saasFile := filepath.Join(dir, "saas-example.yaml")
+	err := os.WriteFile(saasFile, []byte("PACKAGE_TAG: abc123\n"), 0600)
+	if err != nil {
+		t.Fatalf("failed to write SAAS file: %v", err)
+	}
+
+	a := AppInterface{GitDirectory: dir}
+	err = a.UpdatePackageTag(saasFile, "abc123", "def456", "promote-example-package-def456")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cmd = exec.Command("git", "rev-parse", "--abbrev-ref", "HEAD")
+	cmd.Dir = dir
+	output, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("git rev-parse failed: %v", err)
+	}
+	if branch := strings.TrimSpace(string(output)); branch != "promote-example-package-def456" {
+		t.Errorf("expected the package tag to be updated on the promotion branch, got %s", branch)
+	}
+	content, err := os.ReadFile(saasFile)
+	if err != nil {
+		t.Fatalf("failed to read SAAS file: %v", err)
+	}
+	if string(content) != "PACKAGE_TAG: def456\n" {
+		t.Errorf("expected the package tag to be updated, got %s", content)
+	}
+}
@@ -0,0 +1,234 @@
+package git
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultProductionNamespacePattern matches the namespace $ref of the production targets of SAAS files
+const DefaultProductionNamespacePattern = `hivep|backplanep|production`
+
+// ValidateSaasFile ensures the content of the given SAAS file has the structure needed to promote it
+func ValidateSaasFile(saasFile string, saasFileContent []byte) error {
+	var service Service
+	err := yaml.Unmarshal(saasFileContent, &service)
+	if err != nil {
+		return fmt.Errorf("%w: SAAS file %s is not valid yaml: %v", ErrInvalidSaasFile, saasFile, err)
+	}
+
+	if len(service.ResourceTemplates) == 0 {
+		return fmt.Errorf("%w: SAAS file %s has no resourceTemplates", ErrInvalidSaasFile, saasFile)
+	}
+
+	for _, resourceTemplate := range service.ResourceTemplates {
+		if len(resourceTemplate.Targets) > 0 {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: SAAS file %s has no targets in any of its resourceTemplates", ErrInvalidSaasFile, saasFile)
+}
+
+// SaasTarget is a target of a SAAS file along with its position in the file
+type SaasTarget struct {
+	ResourceTemplateIndex int
+	TargetIndex           int
+	ResourceTemplate      string
+	Namespace             string
+	Ref                   string
+}
+
+// GetSaasTargets returns every target of every resource template in the SAAS file
+func GetSaasTargets(saasFileContent []byte) ([]SaasTarget, error) {
+	var service Service
+	err := yaml.Unmarshal(saasFileContent, &service)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse SAAS file: %v", err)
+	}
+
+	var targets []SaasTarget
+	for i, resourceTemplate := range service.ResourceTemplates {
+		for j, target := range resourceTemplate.Targets {
+			targets = append(targets, SaasTarget{
+				ResourceTemplateIndex: i,
+				TargetIndex:           j,
+				ResourceTemplate:      resourceTemplate.Name,
+				Namespace:             target.Namespace["$ref"],
+				Ref:                   target.Ref,
+			})
+		}
+	}
+
+	return targets, nil
+}
+
+// findProductionTargets returns every target whose namespace $ref matches the given pattern.
+// Package resource templates are skipped, as those are promoted separately.
+func findProductionTargets(service Service, namespacePattern *regexp.Regexp) []SaasTarget {
+	var targets []SaasTarget
+	for i, resourceTemplate := range service.ResourceTemplates {
+		if strings.Contains(resourceTemplate.Name, "package") {
+			continue
+		}
+		for j, target := range resourceTemplate.Targets {
+			if namespacePattern.MatchString(target.Namespace["$ref"]) {
+				targets = append(targets, SaasTarget{
+					ResourceTemplateIndex: i,
+					TargetIndex:           j,
+					ResourceTemplate:      resourceTemplate.Name,
+					Namespace:             target.Namespace["$ref"],
+					Ref:                   target.Ref,
+				})
+			}
+		}
+	}
+
+	return targets
+}
+
+// TargetsRef returns the ref shared by all the given targets, or an error listing them if they disagree
+func TargetsRef(targets []SaasTarget) (string, error) {
+	if len(targets) == 0 {
+		return "", fmt.Errorf("no targets found")
+	}
+
+	ref := targets[0].Ref
+	for _, target := range targets[1:] {
+		if target.Ref != ref {
+			var refs []string
+			for _, t := range targets {
+				refs = append(refs, fmt.Sprintf("\t%s: %s", t.Namespace, t.Ref))
+			}
+			return ref, fmt.Errorf("targets are not at the same ref:\n%s", strings.Join(refs, "\n"))
+		}
+	}
+
+	return ref, nil
+}
+
+// updateTargetRefs sets the ref of each of the given targets to newRef
+func updateTargetRefs(saasFileContent []byte, targets []SaasTarget, newRef string) ([]byte, error) {
+	// The same hash can be shared by other targets or mentioned in comments, none of which should be changed
+	updated := map[string]int{}
+	for _, target := range targets {
+		updated[target.Ref]++
+	}
+	for ref, count := range updated {
+		occurrences := strings.Count(string(saasFileContent), ref)
+		if occurrences > count {
+			log.Warnf("Ref %s appears %d times in the SAAS file. Only %d target ref(s) will be updated", ref, occurrences, count)
+		}
+	}
+
+	var err error
+	for _, target := range targets {
+		// Each edit is made against the re-parsed content, as earlier edits can shift the position of later refs
+		saasFileContent, err = setTargetRef(saasFileContent, target.ResourceTemplateIndex, target.TargetIndex, target.Ref, newRef)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return saasFileContent, nil
+}
+
+// setTargetRef replaces the ref of a single target in the SAAS file content. Only the bytes of the ref itself
+// are changed, so comments and formatting in the rest of the file are preserved.
+func setTargetRef(saasFileContent []byte, resourceTemplateIndex, targetIndex int, currentRef, newRef string) ([]byte, error) {
+	var document yaml.Node
+	err := yaml.Unmarshal(saasFileContent, &document)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse SAAS file: %v", err)
+	}
+
+	refNode, err := targetRefNode(&document, resourceTemplateIndex, targetIndex)
+	if err != nil {
+		return nil, err
+	}
+	if refNode.Value != currentRef {
+		return nil, fmt.Errorf("expected ref of resourceTemplates[%d].targets[%d] to be %s, found %s", resourceTemplateIndex, targetIndex, currentRef, refNode.Value)
+	}
+
+	offset, err := nodeOffset(saasFileContent, refNode)
+	if err != nil {
+		return nil, err
+	}
+	if refNode.Style&(yaml.SingleQuotedStyle|yaml.DoubleQuotedStyle) != 0 {
+		offset++
+	}
+	if !bytes.HasPrefix(saasFileContent[offset:], []byte(currentRef)) {
+		return nil, fmt.Errorf("unable to locate ref %s on line %d of the SAAS file", currentRef, refNode.Line)
+	}
+
+	var newContent bytes.Buffer
+	newContent.Write(saasFileContent[:offset])
+	newContent.WriteString(newRef)
+	newContent.Write(saasFileContent[offset+len(currentRef):])
+	return newContent.Bytes(), nil
+}
+
+// targetRefNode walks the parsed SAAS file down to the ref of the given target
+func targetRefNode(document *yaml.Node, resourceTemplateIndex, targetIndex int) (*yaml.Node, error) {
+	if document.Kind != yaml.DocumentNode || len(document.Content) == 0 {
+		return nil, fmt.Errorf("SAAS file is empty")
+	}
+
+	resourceTemplates := mappingValue(document.Content[0], "resourceTemplates")
+	if resourceTemplates == nil || resourceTemplates.Kind != yaml.SequenceNode || len(resourceTemplates.Content) <= resourceTemplateIndex {
+		return nil, fmt.Errorf("resourceTemplates[%d] not found in SAAS file", resourceTemplateIndex)
+	}
+
+	targets := mappingValue(resourceTemplates.Content[resourceTemplateIndex], "targets")
+	if targets == nil || targets.Kind != yaml.SequenceNode || len(targets.Content) <= targetIndex {
+		return nil, fmt.Errorf("resourceTemplates[%d].targets[%d] not found in SAAS file", resourceTemplateIndex, targetIndex)
+	}
+
+	ref := mappingValue(targets.Content[targetIndex], "ref")
+	if ref == nil || ref.Kind != yaml.ScalarNode {
+		return nil, fmt.Errorf("resourceTemplates[%d].targets[%d].ref not found in SAAS file", resourceTemplateIndex, targetIndex)
+	}
+
+	return ref, nil
+}
+
+// mappingValue returns the value node for the given key of a mapping node, or nil if it isn't present
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// nodeOffset converts the line and column of a node into a byte offset in the content it was parsed from
+func nodeOffset(content []byte, node *yaml.Node) (int, error) {
+	offset := 0
+	for line := 1; line < node.Line; line++ {
+		newline := bytes.IndexByte(content[offset:], '\n')
+		if newline < 0 {
+			return 0, fmt.Errorf("line %d is out of range of the SAAS file", node.Line)
+		}
+		offset += newline + 1
+	}
+
+	// Columns are counted in characters rather than bytes
+	lineContent := content[offset:]
+	if end := bytes.IndexByte(lineContent, '\n'); end >= 0 {
+		lineContent = lineContent[:end]
+	}
+	runes := []rune(string(lineContent))
+	if node.Column-1 > len(runes) {
+		return 0, fmt.Errorf("column %d is out of range on line %d of the SAAS file", node.Column, node.Line)
+	}
+
+	return offset + len(string(runes[:node.Column-1])), nil
+}
@@ -0,0 +1,258 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+const testSaasFile = `---
+# Managed by the SRE team, see the README before editing
+$schema: /app-sre/saas-file-2.yml
+name: saas-example-operator
+resourceTemplates:
+- name: example-operator
+  url: https://github.com/openshift/example-operator
+  targets:
+  # Staging follows master
+  - namespace:
+      $ref: /services/osd-operators/namespaces/hives02ue1/example-operator.yml
+    ref: master
+  - namespace:
+      $ref: /services/osd-operators/namespaces/hivep01ue1/example-operator.yml
+    ref: "0123456789abcdef0123456789abcdef01234567" # production
+    parameters:
+      PREVIOUS_REF: 0123456789abcdef0123456789abcdef01234567
+`
+
+func TestSetTargetRef(t *testing.T) {
+	currentRef := "0123456789abcdef0123456789abcdef01234567"
+	newRef := "fedcba9876543210fedcba9876543210fedcba98"
+
+	newContent, err := setTargetRef([]byte(testSaasFile), 0, 1, currentRef, newRef)
+	if err != nil {
+		t.Fatalf("unexpected error updating ref: %v", err)
+	}
+
+	// Only the production ref should change, leaving the comment and parameter with the same hash untouched
+	expected := strings.Replace(testSaasFile, `ref: "`+currentRef+`"`, `ref: "`+newRef+`"`, 1)
+	if string(newContent) != expected {
+		t.Errorf("expected updated SAAS file to be:\n%s\ngot:\n%s", expected, string(newContent))
+	}
+}
+
+func TestSetTargetRefMismatch(t *testing.T) {
+	_, err := setTargetRef([]byte(testSaasFile), 0, 0, "0123456789abcdef0123456789abcdef01234567", "fedcba9876543210fedcba9876543210fedcba98")
+	if err == nil {
+		t.Errorf("expected an error when the target ref doesn't match the current ref")
+	}
+}
+
+func TestSetTargetRefMissingTarget(t *testing.T) {
+	_, err := setTargetRef([]byte(testSaasFile), 0, 2, "master", "fedcba9876543210fedcba9876543210fedcba98")
+	if err == nil {
+		t.Errorf("expected an error when the target doesn't exist")
+	}
+}
+
+func TestFindProductionTarget(t *testing.T) {
+	var service Service
+	if err := yaml.Unmarshal([]byte(testSaasFile), &service); err != nil {
+		t.Fatalf("failed to unmarshal test SAAS file: %v", err)
+	}
+
+	tests := []struct {
+		Name          string
+		Pattern       string
+		ExpectedFound bool
+		ExpectedIndex int
+	}{
+		{
+			Name:          "Default production pattern",
+			Pattern:       DefaultProductionNamespacePattern,
+			ExpectedFound: true,
+			ExpectedIndex: 1,
+		},
+		{
+			Name:          "Staging namespace",
+			Pattern:       "hives02ue1",
+			ExpectedFound: true,
+			ExpectedIndex: 0,
+		},
+		{
+			Name:          "No matching namespace",
+			Pattern:       "backplanep",
+			ExpectedFound: false,
+		},
+	}
+
+	for _, test := range tests {
+		targets := findProductionTargets(service, regexp.MustCompile(test.Pattern))
+		found := len(targets) > 0
+		if found != test.ExpectedFound {
+			t.Errorf("Test '%s' failed. Expected found to be %t, got %t", test.Name, test.ExpectedFound, found)
+			continue
+		}
+		if found && targets[0].TargetIndex != test.ExpectedIndex {
+			t.Errorf("Test '%s' failed. Expected target %d, got %d", test.Name, test.ExpectedIndex, targets[0].TargetIndex)
+		}
+	}
+}
+
+func TestMultipleProductionTargets(t *testing.T) {
+	currentRef := "0123456789abcdef0123456789abcdef01234567"
+	newRef := "fedcba9876543210fedcba9876543210fedcba98"
+
+	saasFile, err := os.ReadFile("testdata/saas-multi-target.yaml")
+	if err != nil {
+		t.Fatalf("failed to read fixture: %v", err)
+	}
+
+	targets, serviceRepo, err := GetCurrentGitHashFromAppInterface(saasFile, "saas-example-operator", regexp.MustCompile(DefaultProductionNamespacePattern))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if serviceRepo != "https://github.com/openshift/example-operator" {
+		t.Errorf("unexpected service repo %s", serviceRepo)
+	}
+	if len(targets) != 2 {
+		t.Fatalf("expected 2 production targets, got %d: %+v", len(targets), targets)
+	}
+
+	ref, err := TargetsRef(targets)
+	if err != nil {
+		t.Fatalf("expected production targets to agree, got: %v", err)
+	}
+	if ref != currentRef {
+		t.Errorf("expected current ref %s, got %s", currentRef, ref)
+	}
+
+	newContent, err := updateTargetRefs(saasFile, targets, newRef)
+	if err != nil {
+		t.Fatalf("unexpected error updating refs: %v", err)
+	}
+
+	// Both production targets are bumped while the package target keeps its ref
+	if strings.Count(string(newContent), newRef) != 2 || strings.Count(string(newContent), currentRef) != 1 {
+		t.Errorf("expected both production refs to be updated, got:\n%s", string(newContent))
+	}
+}
+
+func TestTargetsRefDisagree(t *testing.T) {
+	targets := []SaasTarget{
+		{Namespace: "hivep01ue1", Ref: "0123456789abcdef0123456789abcdef01234567"},
+		{Namespace: "hivep02ue1", Ref: "fedcba9876543210fedcba9876543210fedcba98"},
+	}
+
+	ref, err := TargetsRef(targets)
+	if err == nil {
+		t.Errorf("expected an error when targets are at different refs")
+	}
+	if ref != targets[0].Ref {
+		t.Errorf("expected the first target's ref to be returned, got %s", ref)
+	}
+}
+
+func TestValidateSaasFile(t *testing.T) {
+	tests := []struct {
+		Name          string
+		Content       string
+		ErrorExpected bool
+	}{
+		{
+			Name:          "Valid SAAS file",
+			Content:       testSaasFile,
+			ErrorExpected: false,
+		},
+		{
+			Name:          "Invalid yaml",
+			Content:       "resourceTemplates: [",
+			ErrorExpected: true,
+		},
+		{
+			Name:          "No resource templates",
+			Content:       "name: saas-example-operator\n",
+			ErrorExpected: true,
+		},
+		{
+			Name:          "No targets",
+			Content:       "name: saas-example-operator\nresourceTemplates:\n- name: example-operator\n  url: https://github.com/openshift/example-operator\n",
+			ErrorExpected: true,
+		},
+	}
+
+	for _, test := range tests {
+		err := ValidateSaasFile("saas-example-operator.yaml", []byte(test.Content))
+		if test.ErrorExpected && err == nil {
+			t.Errorf("Test '%s' failed. Expected error, but got none", test.Name)
+		}
+		if !test.ErrorExpected && err != nil {
+			t.Errorf("Test '%s' failed. Expected no error, but got '%v'", test.Name, err)
+		}
+	}
+}
+
+func TestProductionTargetsOfServiceOverrides(t *testing.T) {
+	tests := []struct {
+		Name               string
+		SaasFile           string
+		NamespacePattern   string
+		ExpectedNamespaces []string
+		ExpectedRef        string
+	}{
+		{
+			Name:               "CAD database pinned to the internal production observability namespace",
+			SaasFile:           "testdata/saas-cad-db.yaml",
+			NamespacePattern:   `app-sre-observability-production-int\.yml`,
+			ExpectedNamespaces: []string{"/services/app-sre-observability/namespaces/app-sre-observability-production-int.yml"},
+			ExpectedRef:        "1111111111111111111111111111111111111111",
+		},
+		{
+			Name:               "CAD skips the production observability dashboards",
+			SaasFile:           "testdata/saas-cad.yaml",
+			NamespacePattern:   `configuration-anomaly-detection-production`,
+			ExpectedNamespaces: []string{"/services/configuration-anomaly-detection/namespaces/configuration-anomaly-detection-production.yml"},
+			ExpectedRef:        "3333333333333333333333333333333333333333",
+		},
+		{
+			Name:             "Every target of the production RHOBS rules and dashboards",
+			SaasFile:         "testdata/saas-rhobs-rules-and-dashboards-production.yaml",
+			NamespacePattern: `.*`,
+			ExpectedNamespaces: []string{
+				"/services/rhobs/observatorium-mst/namespaces/rhobsp02ue1/observatorium-mst-rules.yml",
+				"/services/rhobs/observatorium-mst/namespaces/rhobsp02ue1/observatorium-mst-dashboards.yml",
+			},
+			ExpectedRef: "5555555555555555555555555555555555555555",
+		},
+	}
+
+	for _, test := range tests {
+		saasFile, err := os.ReadFile(test.SaasFile)
+		if err != nil {
+			t.Fatalf("Test '%s' failed. Failed to read fixture: %v", test.Name, err)
+		}
+
+		targets, _, err := GetCurrentGitHashFromAppInterface(saasFile, strings.TrimSuffix(filepath.Base(test.SaasFile), ".yaml"), regexp.MustCompile(test.NamespacePattern))
+		if err != nil {
+			t.Errorf("Test '%s' failed. Unexpected error: %v", test.Name, err)
+			continue
+		}
+		var namespaces []string
+		for _, target := range targets {
+			namespaces = append(namespaces, target.Namespace)
+		}
+		if !reflect.DeepEqual(namespaces, test.ExpectedNamespaces) {
+			t.Errorf("Test '%s' failed. Expected the targets %v, got %v", test.Name, test.ExpectedNamespaces, namespaces)
+			continue
+		}
+		ref, err := TargetsRef(targets)
+		if err != nil || ref != test.ExpectedRef {
+			t.Errorf("Test '%s' failed. Expected the targets to be at %s, got %s: %v", test.Name, test.ExpectedRef, ref, err)
+		}
+	}
+}
@@ -1,14 +1,211 @@
 package git
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
+
+	"github.com/openshift/osdctl/pkg/utils"
+	log "github.com/sirupsen/logrus"
 )
 
-func CheckoutAndCompareGitHash(gitURL, gitHash, currentGitHash string) (string, string, error) {
+// RepoWebURL converts a git remote URL, in either its ssh or https form, into the URL of the repository's web page
+func RepoWebURL(gitURL string) string {
+	webURL := strings.TrimSpace(gitURL)
+	webURL = strings.TrimSuffix(webURL, "/")
+	webURL = strings.TrimSuffix(webURL, ".git")
+
+	switch {
+	case strings.HasPrefix(webURL, "ssh://"):
+		webURL = strings.TrimPrefix(webURL, "ssh://")
+		// Drop the user and any port from the host
+		if at := strings.Index(webURL, "@"); at >= 0 {
+			webURL = webURL[at+1:]
+		}
+		host, path, _ := strings.Cut(webURL, "/")
+		host, _, _ = strings.Cut(host, ":")
+		webURL = "https://" + host + "/" + path
+	case strings.HasPrefix(webURL, "git@"):
+		// scp-like syntax, i.e. git@github.com:openshift/osdctl
+		webURL = "https://" + strings.Replace(strings.TrimPrefix(webURL, "git@"), ":", "/", 1)
+	case strings.HasPrefix(webURL, "http://"):
+		webURL = "https://" + strings.TrimPrefix(webURL, "http://")
+	}
+
+	return webURL
+}
+
+// CompareURL returns the web URL comparing the two given commits of the repository
+func CompareURL(gitURL, fromHash, toHash string) string {
+	webURL := RepoWebURL(gitURL)
+	if strings.Contains(webURL, "gitlab") {
+		return fmt.Sprintf("%s/-/compare/%s...%s", webURL, fromHash, toHash)
+	}
+	return fmt.Sprintf("%s/compare/%s...%s", webURL, fromHash, toHash)
+}
+
+// CheckServiceRepoReachable verifies the service repository can be reached and read before attempting to clone it
+func CheckServiceRepoReachable(gitURL string, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	log.Debugf("Checking service repo %s is reachable", gitURL)
+	cmd := exec.CommandContext(ctx, "git", "ls-remote", "--exit-code", gitURL, "HEAD")
+	// Never prompt for credentials, an auth failure should be reported rather than hang
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	output, err := cmd.CombinedOutput()
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("timed out after %s reaching service repo %s, check your network connection or VPN", timeout, gitURL)
+	}
+	if err != nil {
+		return fmt.Errorf("unable to reach service repo %s, check your network connection and credentials: %v\n%s", gitURL, err, strings.TrimSpace(string(output)))
+	}
+
+	return nil
+}
+
+var (
+	// mirrors maps the service repos mirrored while CacheClones is in effect to their local mirror
+	mirrors   map[string]string
+	mirrorDir string
+	mirrorsMu sync.Mutex
+)
+
+// CacheClones makes every function cloning a service repo clone it from a local mirror, fetched once per repo, until
+// the returned func is called to remove the mirrors. Promoting several services this way only fetches each repo once
+func CacheClones() (func(), error) {
+	dir, err := os.MkdirTemp("", "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temporary directory: %v", err)
+	}
+
+	mirrorsMu.Lock()
+	defer mirrorsMu.Unlock()
+	mirrors = map[string]string{}
+	mirrorDir = dir
+	return func() {
+		mirrorsMu.Lock()
+		defer mirrorsMu.Unlock()
+		mirrors = nil
+		mirrorDir = ""
+		_ = os.RemoveAll(dir)
+	}, nil
+}
+
+// cloneSource returns where a service repo should be cloned from: its local mirror while CacheClones is in effect,
+// mirroring it first if needed, and gitURL otherwise
+func cloneSource(gitURL string) (string, error) {
+	mirrorsMu.Lock()
+	defer mirrorsMu.Unlock()
+	if mirrors == nil {
+		return gitURL, nil
+	}
+	if mirror, ok := mirrors[gitURL]; ok {
+		return mirror, nil
+	}
+
+	mirror := filepath.Join(mirrorDir, strconv.Itoa(len(mirrors)))
+	log.Debugf("Mirroring %s into %s", gitURL, mirror)
+	cmd := exec.Command("git", "clone", "--quiet", "--mirror", gitURL, mirror)
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("failed to clone git repository %s: %v\n%s", gitURL, err, strings.TrimSpace(string(output)))
+	}
+	mirrors[gitURL] = mirror
+	return mirror, nil
+}
+
+// VerifyGitHash clones the repository and resolves the given hash to the full hash of a commit in it. When the hash
+// cannot be found the error lists the latest commits of the repository as suggestions
+func VerifyGitHash(gitURL, gitHash string) (string, error) {
+	tempDir, err := os.MkdirTemp("", "")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temporary directory: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	source, err := cloneSource(gitURL)
+	if err != nil {
+		return "", err
+	}
+	log.Debugf("Cloning %s into %s", source, tempDir)
+	cmd := exec.Command("git", "clone", "--quiet", source, tempDir)
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("failed to clone git repository %s: %v\n%s", gitURL, err, strings.TrimSpace(string(output)))
+	}
+
+	cmd = exec.Command("git", "rev-parse", "--verify", "--quiet", gitHash+"^{commit}")
+	cmd.Dir = tempDir
+	output, err = cmd.Output()
+	if err == nil {
+		return strings.TrimSpace(string(output)), nil
+	}
+
+	cmd = exec.Command("git", "log", "-5", "--format=  %h %s")
+	cmd.Dir = tempDir
+	latest, logErr := cmd.Output()
+	if logErr != nil {
+		return "", fmt.Errorf("%s is not a commit of %s", gitHash, gitURL)
+	}
+	return "", fmt.Errorf("%s is not a commit of %s, the latest commits are:\n%s", gitHash, gitURL, strings.TrimRight(string(latest), "\n"))
+}
+
+// CommitsAhead clones the repository and returns the hash of its HEAD along with the number of commits HEAD is ahead
+// of the given hash
+func CommitsAhead(gitURL, gitHash string) (string, int, error) {
+	tempDir, err := os.MkdirTemp("", "")
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to create temporary directory: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	source, err := cloneSource(gitURL)
+	if err != nil {
+		return "", 0, err
+	}
+	log.Debugf("Cloning %s into %s", source, tempDir)
+	cmd := exec.Command("git", "clone", "--quiet", source, tempDir)
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to clone git repository %s: %v\n%s", gitURL, err, strings.TrimSpace(string(output)))
+	}
+
+	cmd = exec.Command("git", "rev-parse", "HEAD")
+	cmd.Dir = tempDir
+	output, err = cmd.Output()
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to get the HEAD of %s: %v", gitURL, err)
+	}
+	headHash := strings.TrimSpace(string(output))
+
+	cmd = exec.Command("git", "rev-list", "--count", fmt.Sprintf("%s..HEAD", gitHash))
+	cmd.Dir = tempDir
+	output, err = cmd.Output()
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to count the commits of %s since %s: %v", gitURL, gitHash, err)
+	}
+	count, err := strconv.Atoi(strings.TrimSpace(string(output)))
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to parse the commit count '%s': %v", strings.TrimSpace(string(output)), err)
+	}
+	return headHash, count, nil
+}
+
+// CheckoutAndCompareGitHash clones the service repo and resolves the git hash to promote along with the log of commits
+// it adds or rolls back. A rollback with back is only confirmed interactively when confirm is set
+func CheckoutAndCompareGitHash(gitURL, gitHash, currentGitHash string, back int, allowDowngrade bool, confirm bool) (string, string, error) {
 	tempDir, err := ioutil.TempDir("", "")
 	if err != nil {
 		return "", "", fmt.Errorf("failed to create temporary directory: %v", err)
@@ -20,7 +217,12 @@ func CheckoutAndCompareGitHash(gitURL, gitHash, currentGitHash string) (string,
 		return "", "", fmt.Errorf("failed to change directory to temporary directory: %v", err)
 	}
 
-	cmd := exec.Command("git", "clone", gitURL, "source-dir")
+	source, err := cloneSource(gitURL)
+	if err != nil {
+		return "", "", err
+	}
+	log.Debugf("Cloning %s into %s", source, filepath.Join(tempDir, "source-dir"))
+	cmd := exec.Command("git", "clone", source, "source-dir")
 	err = cmd.Run()
 	if err != nil {
 		return "", "", fmt.Errorf("failed to clone git repository: %v", err)
@@ -31,6 +233,10 @@ func CheckoutAndCompareGitHash(gitURL, gitHash, currentGitHash string) (string,
 		return "", "", fmt.Errorf("failed to change directory to source-dir: %v", err)
 	}
 
+	if back > 0 {
+		return resolveRollbackGitHash(currentGitHash, back, confirm)
+	}
+
 	if gitHash == "" {
 		fmt.Printf("No git hash provided. Using HEAD.\n")
 		cmd = exec.Command("git", "rev-parse", "HEAD")
@@ -40,16 +246,86 @@ func CheckoutAndCompareGitHash(gitURL, gitHash, currentGitHash string) (string,
 		}
 		gitHash = strings.TrimSpace(string(output))
 		fmt.Printf("The head githash is %s\n", gitHash)
+	} else {
+		// Resolve abbreviated hashes so they can be compared against the current one
+		cmd = exec.Command("git", "rev-parse", "--verify", gitHash+"^{commit}")
+		output, err := cmd.Output()
+		if err != nil {
+			return "", "", fmt.Errorf("failed to resolve git hash %s: %v", gitHash, err)
+		}
+		gitHash = strings.TrimSpace(string(output))
 	}
 
 	if currentGitHash == gitHash {
-		return "", "", fmt.Errorf("git hash %s is already at HEAD", gitHash)
-	} else {
-		cmd := exec.Command("git", "log", "--no-merges", fmt.Sprintf("%s..%s", currentGitHash, gitHash))
+		// Nothing to promote, the caller is expected to check for this
+		return gitHash, "", nil
+	}
+
+	downgrade, err := isAncestor(gitHash, currentGitHash)
+	if err != nil {
+		log.Warnf("Unable to check whether %s is behind %s: %v", gitHash, currentGitHash, err)
+	} else if downgrade {
+		if !allowDowngrade {
+			return "", "", fmt.Errorf("%s is behind the currently promoted %s, pass --allow-downgrade to roll the service back", gitHash, currentGitHash)
+		}
+		log.Warnf("%s is behind the currently promoted %s, the service will be rolled back", gitHash, currentGitHash)
+		cmd := exec.Command("git", "log", "--no-merges", fmt.Sprintf("%s..%s", gitHash, currentGitHash))
 		commitLog, err := cmd.Output()
 		if err != nil {
 			return "", "", err
 		}
 		return gitHash, string(commitLog), nil
 	}
+
+	cmd = exec.Command("git", "log", "--no-merges", fmt.Sprintf("%s..%s", currentGitHash, gitHash))
+	commitLog, err := cmd.Output()
+	if err != nil {
+		return "", "", err
+	}
+	return gitHash, string(commitLog), nil
+}
+
+// isAncestor reports whether the ancestor commit is reachable from the descendant commit in the current repository
+func isAncestor(ancestor, descendant string) (bool, error) {
+	log.Debugf("Checking whether %s is an ancestor of %s", ancestor, descendant)
+	cmd := exec.Command("git", "merge-base", "--is-ancestor", ancestor, descendant)
+	err := cmd.Run()
+	if err == nil {
+		return true, nil
+	}
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+		return false, nil
+	}
+	return false, err
+}
+
+// resolveRollbackGitHash resolves the commit the given number of steps before the currently promoted git hash,
+// asking the user to confirm it when confirm is set, before returning it along with the log of commits being rolled back
+func resolveRollbackGitHash(currentGitHash string, back int, confirm bool) (string, string, error) {
+	cmd := exec.Command("git", "rev-parse", fmt.Sprintf("%s~%d", currentGitHash, back))
+	output, err := cmd.Output()
+	if err != nil {
+		return "", "", fmt.Errorf("failed to resolve the commit %d steps before %s: %v", back, currentGitHash, err)
+	}
+	gitHash := strings.TrimSpace(string(output))
+
+	cmd = exec.Command("git", "log", "-1", "--format=%H %an %ad%n    %s", gitHash)
+	commit, err := cmd.Output()
+	if err != nil {
+		return "", "", fmt.Errorf("failed to describe commit %s: %v", gitHash, err)
+	}
+	fmt.Printf("Resolved the commit %d steps before %s:\n%s\n", back, currentGitHash, strings.TrimSpace(string(commit)))
+
+	if confirm && !utils.ConfirmPrompt() {
+		return "", "", fmt.Errorf("promotion of %s aborted", gitHash)
+	}
+
+	cmd = exec.Command("git", "log", "--no-merges", fmt.Sprintf("%s..%s", gitHash, currentGitHash))
+	commitLog, err := cmd.Output()
+	if err != nil {
+		return "", "", err
+	}
+	return gitHash, string(commitLog), nil
 }
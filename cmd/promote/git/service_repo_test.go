@@ -0,0 +1,203 @@
+package git
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/openshift/osdctl/cmd/promote/git/testutil"
+)
+
+// restoreWorkingDir returns to the current directory once the test completes, as checking out the service repo changes it
+func restoreWorkingDir(t *testing.T) {
+	t.Helper()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("failed to get working directory: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = os.Chdir(wd)
+	})
+}
+
+func TestCheckoutAndCompareGitHashAlreadyAtTarget(t *testing.T) {
+	restoreWorkingDir(t)
+	repo, hashes := testutil.NewServiceRepo(t, 2)
+	head := hashes[1]
+
+	promotionGitHash, commitLog, err := CheckoutAndCompareGitHash(repo, head[:12], head, 0, false, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if promotionGitHash != head {
+		t.Errorf("expected the abbreviated hash to resolve to %s, got %s", head, promotionGitHash)
+	}
+	if commitLog != "" {
+		t.Errorf("expected no commits to promote, got %s", commitLog)
+	}
+}
+
+func TestCheckoutAndCompareGitHash(t *testing.T) {
+	restoreWorkingDir(t)
+	repo, hashes := testutil.NewServiceRepo(t, 3)
+
+	promotionGitHash, commitLog, err := CheckoutAndCompareGitHash(repo, "", hashes[0], 0, false, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if promotionGitHash != hashes[2] {
+		t.Errorf("expected to promote to HEAD %s, got %s", hashes[2], promotionGitHash)
+	}
+	if strings.Count(commitLog, "\ncommit ")+1 != 2 {
+		t.Errorf("expected 2 commits to be promoted, got:\n%s", commitLog)
+	}
+}
+
+func TestCheckoutAndCompareGitHashDowngrade(t *testing.T) {
+	restoreWorkingDir(t)
+	repo, hashes := testutil.NewServiceRepo(t, 3)
+
+	_, _, err := CheckoutAndCompareGitHash(repo, hashes[0], hashes[2], 0, false, false)
+	if err == nil {
+		t.Fatalf("expected promoting an ancestor of the current hash to be refused")
+	}
+
+	promotionGitHash, commitLog, err := CheckoutAndCompareGitHash(repo, hashes[0], hashes[2], 0, true, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if promotionGitHash != hashes[0] {
+		t.Errorf("expected to roll back to %s, got %s", hashes[0], promotionGitHash)
+	}
+	if strings.Count(commitLog, "\ncommit ")+1 != 2 {
+		t.Errorf("expected 2 commits to be rolled back, got:\n%s", commitLog)
+	}
+}
+
+func TestCheckoutAndCompareGitHashBack(t *testing.T) {
+	restoreWorkingDir(t)
+	repo, hashes := testutil.NewServiceRepo(t, 3)
+
+	promotionGitHash, commitLog, err := CheckoutAndCompareGitHash(repo, "", hashes[2], 2, false, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if promotionGitHash != hashes[0] {
+		t.Errorf("expected to roll back to %s, got %s", hashes[0], promotionGitHash)
+	}
+	if strings.Count(commitLog, "\ncommit ")+1 != 2 {
+		t.Errorf("expected 2 commits to be rolled back, got:\n%s", commitLog)
+	}
+}
+
+func TestCacheClones(t *testing.T) {
+	restoreWorkingDir(t)
+	repo, hashes := testutil.NewServiceRepo(t, 2)
+
+	cleanup, err := CacheClones()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer cleanup()
+
+	verified, err := VerifyGitHash(repo, hashes[1][:7])
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if verified != hashes[1] {
+		t.Errorf("expected %s to be verified, got %s", hashes[1], verified)
+	}
+
+	// The repo was mirrored when verifying the hash, so comparing must not need the original any more
+	err = os.RemoveAll(repo)
+	if err != nil {
+		t.Fatalf("failed to remove the service repo: %v", err)
+	}
+	promotionGitHash, _, err := CheckoutAndCompareGitHash(repo, verified, hashes[0], 0, false, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if promotionGitHash != hashes[1] {
+		t.Errorf("expected to promote to %s, got %s", hashes[1], promotionGitHash)
+	}
+}
+
+func TestCompareURL(t *testing.T) {
+	tests := []struct {
+		Name     string
+		GitURL   string
+		Expected string
+	}{
+		{
+			Name:     "GitHub https",
+			GitURL:   "https://github.com/openshift/osdctl",
+			Expected: "https://github.com/openshift/osdctl/compare/abc...def",
+		},
+		{
+			Name:     "GitHub https with .git suffix",
+			GitURL:   "https://github.com/openshift/osdctl.git",
+			Expected: "https://github.com/openshift/osdctl/compare/abc...def",
+		},
+		{
+			Name:     "GitHub scp-like ssh",
+			GitURL:   "git@github.com:openshift/osdctl.git",
+			Expected: "https://github.com/openshift/osdctl/compare/abc...def",
+		},
+		{
+			Name:     "GitLab ssh with port",
+			GitURL:   "ssh://git@gitlab.cee.redhat.com:2222/service/app-interface.git",
+			Expected: "https://gitlab.cee.redhat.com/service/app-interface/-/compare/abc...def",
+		},
+		{
+			Name:     "GitLab https",
+			GitURL:   "https://gitlab.cee.redhat.com/service/app-interface",
+			Expected: "https://gitlab.cee.redhat.com/service/app-interface/-/compare/abc...def",
+		},
+	}
+
+	for _, test := range tests {
+		actual := CompareURL(test.GitURL, "abc", "def")
+		if actual != test.Expected {
+			t.Errorf("Test '%s' failed. Expected %s, got %s", test.Name, test.Expected, actual)
+		}
+	}
+}
+
+func TestVerifyGitHash(t *testing.T) {
+	repo, hashes := testutil.NewServiceRepo(t, 3)
+
+	gitHash, err := VerifyGitHash(repo, hashes[1][:7])
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gitHash != hashes[1] {
+		t.Errorf("expected %s to resolve to %s, got %s", hashes[1][:7], hashes[1], gitHash)
+	}
+
+	_, err = VerifyGitHash(repo, "0000000")
+	if err == nil {
+		t.Fatalf("expected an error for a hash that is not in the repository")
+	}
+	if !strings.Contains(err.Error(), hashes[2][:7]) {
+		t.Errorf("expected the latest commit %s to be suggested, got: %v", hashes[2][:7], err)
+	}
+}
+
+func TestCommitsAhead(t *testing.T) {
+	repo, hashes := testutil.NewServiceRepo(t, 4)
+
+	headHash, count, err := CommitsAhead(repo, hashes[1])
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if headHash != hashes[3] {
+		t.Errorf("expected HEAD to be %s, got %s", hashes[3], headHash)
+	}
+	if count != 2 {
+		t.Errorf("expected HEAD to be 2 commits ahead of %s, got %d", hashes[1], count)
+	}
+
+	if _, _, err := CommitsAhead(repo, "0000000"); err == nil {
+		t.Errorf("expected an error for a hash that is not in the repository")
+	}
+}
@@ -0,0 +1,38 @@
+// Package testutil provides the git fixtures shared by the tests of the promote commands
+package testutil
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+// NewServiceRepo creates a local git repository with the given number of commits, returning its path and the commit
+// hashes in order
+func NewServiceRepo(t *testing.T, commits int) (string, []string) {
+	t.Helper()
+	dir := t.TempDir()
+
+	runGit := func(args ...string) string {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@example.com",
+			"GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@example.com",
+		)
+		output, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, output)
+		}
+		return strings.TrimSpace(string(output))
+	}
+
+	runGit("init", "-q")
+	var hashes []string
+	for i := 0; i < commits; i++ {
+		runGit("commit", "-q", "--allow-empty", "-m", "commit")
+		hashes = append(hashes, runGit("rev-parse", "HEAD"))
+	}
+	return dir, hashes
+}
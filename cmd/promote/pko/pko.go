@@ -1,10 +1,18 @@
 package pko
 
 import (
+	"errors"
 	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"syscall"
 
 	"github.com/openshift/osdctl/cmd/promote/git"
 	"github.com/openshift/osdctl/cmd/promote/saas"
+	"github.com/openshift/osdctl/pkg/printer"
+	"github.com/openshift/osdctl/pkg/utils"
+	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	cmdutil "k8s.io/kubectl/pkg/cmd/util"
 )
@@ -17,19 +25,39 @@ func NewCmdPKO() *cobra.Command {
 		Args:              cobra.NoArgs,
 		DisableAutoGenTag: true,
 		Example: `
+		# List all package-operator services and their current package tag
+		osdctl promote package --list
+
 		# Promote a package-operator service
-		osdctl promote package --serviceName <serviceName> --gitHash <git-hash>`,
+		osdctl promote package --serviceName <serviceName> --gitHash <git-hash>
+
+		# Show the package tag change without committing it
+		osdctl promote package --serviceName <serviceName> --tag <tag> --dry-run`,
 		Run: func(cmd *cobra.Command, args []string) {
+			if ops.list {
+				appInterface := git.BootstrapOsdCtlForAppInterfaceAndServicePromotions(ops.appInterfaceCheckoutDir)
+				cmdutil.CheckErr(listPackages(os.Stdout, appInterface, ops.hcp))
+				return
+			}
+
 			cmdutil.CheckErr(ops.ValidatePKOOptions())
 			appInterface := git.BootstrapOsdCtlForAppInterfaceAndServicePromotions(ops.appInterfaceCheckoutDir)
+			appInterface.DryRun, _ = cmd.Flags().GetBool(git.DryRunFlag)
 
-			cmdutil.CheckErr(PromotePackage(appInterface, ops.serviceName, ops.packageTag, ops.hcp))
+			cmdutil.CheckErr(PromotePackage(appInterface, ops.serviceName, ops.packageTag, ops.author, ops.hcp, ops.allowDirty, ops.force))
 		},
 	}
+	pkoCmd.Flags().BoolVarP(&ops.list, "list", "l", false, "List all package-operator services and their current package tag. Use with --hcp for HyperShift deployments")
 	pkoCmd.Flags().StringVarP(&ops.serviceName, "serviceName", "n", "", "Service getting promoted")
+	_ = pkoCmd.RegisterFlagCompletionFunc("serviceName", saas.ServiceNameCompletion(&ops.appInterfaceCheckoutDir, saas.PackageSaasDir))
 	pkoCmd.Flags().StringVarP(&ops.packageTag, "tag", "t", "", "Package tag being promoted to")
-	pkoCmd.Flags().StringVarP(&ops.appInterfaceCheckoutDir, "appInterfaceDir", "", "", "location of app-interfache checkout. Falls back to `pwd` and "+git.DefaultAppInterfaceDirectory())
+	pkoCmd.Flags().StringVarP(&ops.appInterfaceCheckoutDir, "appInterfaceDir", "", "", "location of app-interfache checkout. Falls back to $"+git.AppInterfacePathEnv+", `pwd` and "+git.DefaultAppInterfaceDirectory())
 	pkoCmd.Flags().BoolVar(&ops.hcp, "hcp", false, "The service being promoted conforms to the HyperShift progressive delivery definition")
+	pkoCmd.Flags().StringVar(&ops.author, "author", "", "Override the promotion commit author, in the form 'Name <email>'. Defaults to the git config of the app-interface checkout")
+	pkoCmd.Flags().BoolVarP(&ops.force, "force", "f", false, "Promote even if the promotion branch already exists on the origin remote")
+	pkoCmd.Flags().BoolVar(&ops.allowDirty, "allow-dirty", false, "Allow promoting from an app-interface checkout with uncommitted changes")
+	pkoCmd.MarkFlagsMutuallyExclusive("list", "serviceName")
+	pkoCmd.MarkFlagsMutuallyExclusive("list", "tag")
 	return pkoCmd
 }
 
@@ -38,7 +66,11 @@ type pkoOptions struct {
 	serviceName             string
 	packageTag              string
 	appInterfaceCheckoutDir string
+	author                  string
 	hcp                     bool
+	allowDirty              bool
+	force                   bool
+	list                    bool
 }
 
 func (p pkoOptions) ValidatePKOOptions() error {
@@ -48,11 +80,19 @@ func (p pkoOptions) ValidatePKOOptions() error {
 	if p.packageTag == "" {
 		return fmt.Errorf("a new package tag must be provided with '--tag' or '-t'")
 	}
-	return nil
+	return git.ValidateCommitAuthor(p.author)
 }
 
-func PromotePackage(appInterface git.AppInterface, serviceName string, packageTag string, hcp bool) error {
-	services, err := saas.GetServiceNames(appInterface, saas.OSDSaasDir, saas.BPSaasDir, saas.CADSaasDir)
+// PromotePackage updates the package tag of the given service. With appInterface.DryRun set the change is only printed
+func PromotePackage(appInterface git.AppInterface, serviceName string, packageTag string, author string, hcp bool, allowDirty bool, force bool) error {
+	if !allowDirty && !appInterface.DryRun {
+		err := appInterface.CheckWorkingTreeClean()
+		if err != nil {
+			return fmt.Errorf("%w\nCommit or stash these changes, or pass --allow-dirty to promote anyway", err)
+		}
+	}
+
+	services, err := saas.GetServiceNames(appInterface, saas.PackageSaasDir)
 	if err != nil {
 		return err
 	}
@@ -76,14 +116,38 @@ func PromotePackage(appInterface git.AppInterface, serviceName string, packageTa
 		return fmt.Errorf("current hash is already at '%s'. Nothing to do", packageTag)
 	}
 
+	packageRepo, err := git.GetPackageRepoFromAppInterface(saasFile)
+	if err != nil {
+		return err
+	}
+	stopVerify := utils.Timings.Start("git clone and verify " + serviceName)
+	_, err = git.VerifyGitHash(packageRepo, packageTag)
+	stopVerify()
+	if err != nil {
+		return err
+	}
+
+	if appInterface.DryRun {
+		fmt.Printf("SAAS File: %s\n", saasFile)
+		fmt.Printf("Service: %s\n", serviceName)
+		fmt.Printf("PACKAGE_TAG: %s -> %s\n", currentTag, packageTag)
+		fmt.Println("Dry run, no branch or commit was created")
+		return nil
+	}
+
 	branchName := fmt.Sprintf("promote-%s-package-%s", serviceName, packageTag)
+	err = appInterface.CheckPromotionBranch(branchName, force)
+	if err != nil {
+		return err
+	}
+
 	err = appInterface.UpdatePackageTag(saasFile, currentTag, packageTag, branchName)
 	if err != nil {
 		return err
 	}
 
 	commitMessage := fmt.Sprintf("Promote %s package to %s", serviceName, packageTag)
-	err = appInterface.CommitSaasFile(saasFile, commitMessage)
+	_, err = appInterface.CommitSaasFile(saasFile, commitMessage, author, false)
 	if err != nil {
 		return err
 	}
@@ -93,9 +157,49 @@ func PromotePackage(appInterface git.AppInterface, serviceName string, packageTa
 	fmt.Printf("Service: %s\n", serviceName)
 	fmt.Printf("Previous Tag: %s\n", currentTag)
 	fmt.Printf("New Tag: %s\n", packageTag)
+	if mergeRequestURL := appInterface.MergeRequestURL(branchName); mergeRequestURL != "" {
+		fmt.Printf("Once pushed, create the merge request at: %s\n", mergeRequestURL)
+	}
 	return nil
 }
 
+// listPackages prints the services of the package SAAS directory deploying a package, along with their current
+// production package tag. SAAS files that can't be read are reported and skipped
+func listPackages(out io.Writer, appInterface git.AppInterface, hcp bool) error {
+	services, err := saas.GetServiceNames(appInterface, saas.PackageSaasDir)
+	if err != nil {
+		return err
+	}
+
+	table := printer.NewTablePrinter(out, 20, 1, 3, ' ')
+	table.AddRow([]string{"SERVICE", "PACKAGE TAG"})
+	for _, service := range services {
+		saasFile, err := saas.GetSaasDir(service, !hcp, hcp)
+		if err != nil {
+			return err
+		}
+		if _, err := os.Stat(saasFile); err != nil {
+			// Not every service has a HyperShift deployment, and single file SAAS entries never have one
+			if errors.Is(err, fs.ErrNotExist) || errors.Is(err, syscall.ENOTDIR) {
+				continue
+			}
+			return fmt.Errorf("failed to read SAAS file of %s: %w", service, err)
+		}
+
+		packageTag, err := git.GetCurrentPackageTagFromAppInterface(saasFile)
+		if err != nil {
+			log.Warnf("Skipping %s: %v", service, err)
+			continue
+		}
+		if packageTag == "" {
+			continue
+		}
+		table.AddRow([]string{service, packageTag})
+	}
+
+	return table.Flush()
+}
+
 func updatePackageHash(gitHash, saasFile string) error {
 	return nil
 }
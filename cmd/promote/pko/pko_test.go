@@ -0,0 +1,54 @@
+package pko
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/openshift/osdctl/cmd/promote/git"
+)
+
+func TestListPackages(t *testing.T) {
+	appInterface := git.AppInterface{GitDirectory: "testdata/app-interface"}
+
+	tests := []struct {
+		name     string
+		hcp      bool
+		expected []string
+		missing  []string
+	}{
+		{
+			name:     "OSD deployments",
+			expected: []string{"saas-dir-operator abc2222", "saas-file-operator abc1111"},
+		},
+		{
+			name:     "HyperShift deployments skip single file SAAS entries",
+			hcp:      true,
+			expected: []string{"saas-dir-operator abc3333"},
+			missing:  []string{"saas-file-operator"},
+		},
+	}
+
+	for _, test := range tests {
+		var out bytes.Buffer
+		err := listPackages(&out, appInterface, test.hcp)
+		if err != nil {
+			t.Errorf("Test '%s' failed. Unexpected error: %v", test.name, err)
+			continue
+		}
+		rows := map[string]bool{}
+		for _, line := range strings.Split(out.String(), "\n") {
+			rows[strings.Join(strings.Fields(line), " ")] = true
+		}
+		for _, row := range test.expected {
+			if !rows[row] {
+				t.Errorf("Test '%s' failed. Expected the row '%s', got:\n%s", test.name, row, out.String())
+			}
+		}
+		for _, service := range test.missing {
+			if strings.Contains(out.String(), service) {
+				t.Errorf("Test '%s' failed. Expected %s not to be listed, got:\n%s", test.name, service, out.String())
+			}
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package saas
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/openshift/osdctl/cmd/promote/git"
+	"github.com/openshift/osdctl/pkg/printer"
+)
+
+// batchEntry is a single service promotion read from a --from-file manifest
+type batchEntry struct {
+	serviceName string
+	gitHash     string
+}
+
+// parseBatchFile reads a promotion manifest with one 'service=hash' entry per line. Blank lines and lines starting
+// with '#' are ignored
+func parseBatchFile(path string) ([]batchEntry, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer file.Close()
+
+	var entries []batchEntry
+	seen := map[string]int{}
+	scanner := bufio.NewScanner(file)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		serviceName, gitHash, found := strings.Cut(line, "=")
+		serviceName = strings.TrimSpace(serviceName)
+		gitHash = strings.TrimSpace(gitHash)
+		if !found || serviceName == "" || gitHash == "" {
+			return nil, fmt.Errorf("%s:%d: expected an entry in the form 'service=hash', got '%s'", path, lineNumber, line)
+		}
+		if previous, ok := seen[serviceName]; ok {
+			return nil, fmt.Errorf("%s:%d: service %s is already promoted on line %d", path, lineNumber, serviceName, previous)
+		}
+		seen[serviceName] = lineNumber
+
+		entries = append(entries, batchEntry{serviceName: serviceName, gitHash: gitHash})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("%s does not contain any services to promote", path)
+	}
+
+	return entries, nil
+}
+
+// verifyBatchEntry checks a --from-file entry can be promoted and returns it with the canonical service name and the
+// full git hash it resolves to in the service repo
+func verifyBatchEntry(entry batchEntry, ops *saasOptions) (batchEntry, error) {
+	err := checkServicePattern(entry.serviceName, ops.servicePattern)
+	if err != nil {
+		return entry, err
+	}
+	serviceName, err := ValidateServiceName(ServicesSlice, entry.serviceName)
+	if err != nil {
+		return entry, err
+	}
+	saasDir, err := GetSaasDir(serviceName, ops.osd, ops.hcp)
+	if err != nil {
+		return entry, err
+	}
+
+	namespacePattern, err := ops.targetNamespacePattern(serviceName)
+	if err != nil {
+		return entry, err
+	}
+	serviceData, err := os.ReadFile(saasDir)
+	if err != nil {
+		return entry, fmt.Errorf("failed to read SAAS file: %v", err)
+	}
+	err = git.ValidateSaasFile(saasDir, serviceData)
+	if err != nil {
+		return entry, err
+	}
+	_, serviceRepo, err := git.GetCurrentGitHashFromAppInterface(serviceData, serviceName, namespacePattern)
+	if err != nil {
+		return entry, fmt.Errorf("failed to get service repo of %s: %w", serviceName, err)
+	}
+
+	gitHash, err := git.VerifyGitHash(serviceRepo, entry.gitHash)
+	if err != nil {
+		return entry, fmt.Errorf("cannot promote %s: %w", serviceName, err)
+	}
+
+	return batchEntry{serviceName: serviceName, gitHash: gitHash}, nil
+}
+
+// batchPromotion promotes every service of the --from-file manifest. All entries, including their git hashes, are
+// verified before any branch is created, so a typo does not leave a partially promoted set of services behind
+func batchPromotion(appInterface git.AppInterface, ops *saasOptions) error {
+	entries, err := parseBatchFile(ops.fromFile)
+	if err != nil {
+		return err
+	}
+
+	_, err = GetServiceNames(appInterface, ops.saasDirs...)
+	if err != nil {
+		return err
+	}
+
+	// Verifying an entry fetches its service repo, keep it for the promotion rather than cloning it again
+	cleanupClones, err := git.CacheClones()
+	if err != nil {
+		return err
+	}
+	defer cleanupClones()
+
+	for i, entry := range entries {
+		entries[i], err = verifyBatchEntry(entry, ops)
+		if err != nil {
+			return err
+		}
+	}
+
+	var failed int
+	table := printer.NewTablePrinter(os.Stdout, 20, 1, 3, ' ')
+	table.AddRow([]string{"SERVICE", "FROM", "TO", "BRANCH", "STATUS"})
+	for _, entry := range entries {
+		entryOps := *ops
+		entryOps.serviceName = entry.serviceName
+		entryOps.gitHash = entry.gitHash
+
+		fmt.Printf("\n### Promoting %s to %s ###\n", entry.serviceName, entry.gitHash)
+		result, err := servicePromotion(appInterface, &entryOps)
+		if err != nil {
+			failed++
+			fmt.Printf("Error while promoting service: %v\n", err)
+			table.AddRow([]string{entry.serviceName, "", entry.gitHash, "", "failed: " + err.Error()})
+			continue
+		}
+
+		status := "ready to push"
+		if result.CommitSHA == "" && result.FromHash == result.ToHash {
+			status = "nothing to promote"
+		} else if result.CommitSHA == "" {
+			status = "valid"
+		}
+		table.AddRow([]string{result.Service, result.FromHash, result.ToHash, result.Branch, status})
+	}
+
+	fmt.Println("")
+	err = table.Flush()
+	if err != nil {
+		return err
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d promotions failed", failed, len(entries))
+	}
+	return nil
+}
@@ -0,0 +1,147 @@
+package saas
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/openshift/osdctl/cmd/promote/git"
+	"github.com/openshift/osdctl/cmd/promote/git/testutil"
+)
+
+func TestParseBatchFile(t *testing.T) {
+	tests := []struct {
+		name        string
+		content     string
+		expected    []batchEntry
+		expectError bool
+	}{
+		{
+			name:    "entries with comments and blank lines",
+			content: "# release 42\nsaas-foo=abc123\n\n  bar = def456  \n",
+			expected: []batchEntry{
+				{serviceName: "saas-foo", gitHash: "abc123"},
+				{serviceName: "bar", gitHash: "def456"},
+			},
+		},
+		{
+			name:        "missing hash",
+			content:     "saas-foo=\n",
+			expectError: true,
+		},
+		{
+			name:        "missing separator",
+			content:     "saas-foo abc123\n",
+			expectError: true,
+		},
+		{
+			name:        "duplicate service",
+			content:     "saas-foo=abc123\nsaas-foo=def456\n",
+			expectError: true,
+		},
+		{
+			name:        "no entries",
+			content:     "# nothing to promote\n",
+			expectError: true,
+		},
+	}
+
+	for _, test := range tests {
+		path := filepath.Join(t.TempDir(), "promotions.txt")
+		err := os.WriteFile(path, []byte(test.content), 0600)
+		if err != nil {
+			t.Fatalf("failed to write batch file: %v", err)
+		}
+
+		entries, err := parseBatchFile(path)
+		if test.expectError {
+			if err == nil {
+				t.Errorf("Test '%s' failed. Expected an error, got %v", test.name, entries)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Test '%s' failed. Unexpected error: %v", test.name, err)
+			continue
+		}
+		if len(entries) != len(test.expected) {
+			t.Errorf("Test '%s' failed. Expected %v, got %v", test.name, test.expected, entries)
+			continue
+		}
+		for i := range entries {
+			if entries[i] != test.expected[i] {
+				t.Errorf("Test '%s' failed. Expected %v, got %v", test.name, test.expected[i], entries[i])
+			}
+		}
+	}
+}
+
+func TestVerifyBatchEntry(t *testing.T) {
+	serviceRepo, hashes := testutil.NewServiceRepo(t, 1)
+	head := hashes[0]
+
+	appInterface := git.AppInterface{GitDirectory: t.TempDir()}
+	saasDir := filepath.Join(appInterface.GitDirectory, OSDSaasDir)
+	err := os.MkdirAll(saasDir, 0700)
+	if err != nil {
+		t.Fatalf("failed to create SAAS directory: %v", err)
+	}
+	saasFile := fmt.Sprintf(`name: saas-batch-operator
+resourceTemplates:
+- name: batch-operator
+  url: %s
+  targets:
+  - namespace:
+      $ref: /services/osd-operators/namespaces/hivep01ue1/batch-operator.yml
+    ref: %s
+`, serviceRepo, head)
+	err = os.WriteFile(filepath.Join(saasDir, "saas-batch-operator.yaml"), []byte(saasFile), 0600)
+	if err != nil {
+		t.Fatalf("failed to create SAAS file: %v", err)
+	}
+	_, err = GetServiceNames(appInterface, OSDSaasDir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		entry       batchEntry
+		expected    batchEntry
+		expectError bool
+	}{
+		{
+			name:     "abbreviated hash",
+			entry:    batchEntry{serviceName: "saas-batch-operator", gitHash: head[:7]},
+			expected: batchEntry{serviceName: "saas-batch-operator", gitHash: head},
+		},
+		{
+			name:        "hash missing from the service repo",
+			entry:       batchEntry{serviceName: "saas-batch-operator", gitHash: "0123456789abcdef0123456789abcdef01234567"},
+			expectError: true,
+		},
+		{
+			name:        "unknown service",
+			entry:       batchEntry{serviceName: "saas-missing-operator", gitHash: head},
+			expectError: true,
+		},
+	}
+
+	for _, test := range tests {
+		entry, err := verifyBatchEntry(test.entry, &saasOptions{osd: true})
+		if test.expectError {
+			if err == nil {
+				t.Errorf("Test '%s' failed. Expected an error, got %v", test.name, entry)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Test '%s' failed. Unexpected error: %v", test.name, err)
+			continue
+		}
+		if entry != test.expected {
+			t.Errorf("Test '%s' failed. Expected %v, got %v", test.name, test.expected, entry)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package saas
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+
+	"github.com/openshift/osdctl/cmd/promote/git"
+	"github.com/spf13/viper"
+)
+
+const (
+	// SaasDirsConfigKey lists app-interface directories, in the osdctl config file, searched for SAAS files in
+	// addition to the default ones
+	SaasDirsConfigKey = "promote_saas_dirs"
+	// ReplaceDefaultSaasDirsConfigKey makes SaasDirsConfigKey replace the default directories instead of adding to them
+	ReplaceDefaultSaasDirsConfigKey = "promote_replace_default_saas_dirs"
+	// ProductionPatternsConfigKey lists, in the osdctl config file, the production namespace patterns of services whose
+	// production targets don't follow the common namespace naming. They are checked before DefaultProductionPatterns
+	ProductionPatternsConfigKey = "promote_production_patterns"
+)
+
+// ProductionPattern selects the production targets of the services whose name matches Service with the Namespace
+// pattern instead of git.DefaultProductionNamespacePattern
+type ProductionPattern struct {
+	Service   string `mapstructure:"service"`
+	Namespace string `mapstructure:"namespace"`
+}
+
+// DefaultProductionPatterns are the known services whose production targets don't follow the common namespace naming.
+// The first matching pattern is used, so more specific services come first
+var DefaultProductionPatterns = []ProductionPattern{
+	{
+		// The database is only promoted to the internal production observability namespace
+		Service:   `^saas-configuration-anomaly-detection-db$`,
+		Namespace: `app-sre-observability-production-int\.yml`,
+	},
+	{
+		Service:   `configuration-anomaly-detection`,
+		Namespace: `configuration-anomaly-detection-production`,
+	},
+	{
+		// The production rules and dashboards have a SAAS file of their own, all of its targets are production
+		Service:   `rhobs-rules-and-dashboards.*production`,
+		Namespace: `.*`,
+	},
+	{
+		Service:   `saas-backplane-api`,
+		Namespace: `backplanep`,
+	},
+}
+
+// DefaultSaasDirs are the app-interface directories searched for SAAS files unless the osdctl config file says otherwise
+var DefaultSaasDirs = []string{OSDSaasDir, BPSaasDir, CADSaasDir}
+
+// ConfiguredSaasDirs returns the app-interface directories searched for SAAS files: DefaultSaasDirs along with the
+// directories set in the osdctl config file, or only the latter when they replace the defaults
+func ConfiguredSaasDirs() []string {
+	configured := viper.GetStringSlice(SaasDirsConfigKey)
+	if len(configured) > 0 && viper.GetBool(ReplaceDefaultSaasDirsConfigKey) {
+		return configured
+	}
+
+	saasDirs := append([]string{}, DefaultSaasDirs...)
+	for _, saasDir := range configured {
+		if !slices.Contains(saasDirs, saasDir) {
+			saasDirs = append(saasDirs, saasDir)
+		}
+	}
+	return saasDirs
+}
+
+// ProductionNamespacePattern returns the pattern matching the namespace $ref of the production targets of the service:
+// the first of the patterns set in the osdctl config file and DefaultProductionPatterns matching the service, or
+// git.DefaultProductionNamespacePattern
+func ProductionNamespacePattern(serviceName string) (*regexp.Regexp, error) {
+	var configured []ProductionPattern
+	err := viper.UnmarshalKey(ProductionPatternsConfigKey, &configured)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s in the osdctl config file: %w", ProductionPatternsConfigKey, err)
+	}
+
+	for _, pattern := range append(configured, DefaultProductionPatterns...) {
+		service, err := regexp.Compile(pattern.Service)
+		if err != nil {
+			return nil, fmt.Errorf("invalid service pattern '%s' in %s: %w", pattern.Service, ProductionPatternsConfigKey, err)
+		}
+		if !service.MatchString(serviceName) {
+			continue
+		}
+		namespace, err := regexp.Compile(pattern.Namespace)
+		if err != nil {
+			return nil, fmt.Errorf("invalid namespace pattern '%s' in %s: %w", pattern.Namespace, ProductionPatternsConfigKey, err)
+		}
+		return namespace, nil
+	}
+	return regexp.MustCompile(git.DefaultProductionNamespacePattern), nil
+}
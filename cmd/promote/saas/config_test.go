@@ -0,0 +1,91 @@
+package saas
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/openshift/osdctl/cmd/promote/git"
+	"github.com/spf13/viper"
+)
+
+func TestConfiguredSaasDirs(t *testing.T) {
+	tests := []struct {
+		name       string
+		configured []string
+		replace    bool
+		expected   []string
+	}{
+		{
+			name:     "defaults",
+			expected: DefaultSaasDirs,
+		},
+		{
+			name:       "added to the defaults",
+			configured: []string{"data/services/example/cicd/saas", OSDSaasDir},
+			expected:   append(append([]string{}, DefaultSaasDirs...), "data/services/example/cicd/saas"),
+		},
+		{
+			name:       "replacing the defaults",
+			configured: []string{"data/services/example/cicd/saas"},
+			replace:    true,
+			expected:   []string{"data/services/example/cicd/saas"},
+		},
+		{
+			name:     "replacing the defaults without directories",
+			replace:  true,
+			expected: DefaultSaasDirs,
+		},
+	}
+
+	for _, test := range tests {
+		viper.Set(SaasDirsConfigKey, test.configured)
+		viper.Set(ReplaceDefaultSaasDirsConfigKey, test.replace)
+		actual := ConfiguredSaasDirs()
+		if !reflect.DeepEqual(actual, test.expected) {
+			t.Errorf("Test '%s' failed. Expected %v, got %v", test.name, test.expected, actual)
+		}
+	}
+	viper.Reset()
+}
+
+func TestProductionNamespacePattern(t *testing.T) {
+	tests := []struct {
+		service    string
+		configured []map[string]string
+		expected   string
+	}{
+		{service: "saas-configuration-anomaly-detection-db", expected: `app-sre-observability-production-int\.yml`},
+		{service: "saas-configuration-anomaly-detection", expected: `configuration-anomaly-detection-production`},
+		{service: "saas-rhobs-rules-and-dashboards-production", expected: `.*`},
+		{service: "saas-backplane-api", expected: `backplanep`},
+		{service: "saas-example-operator", expected: git.DefaultProductionNamespacePattern},
+		{
+			service:    "saas-example-operator",
+			configured: []map[string]string{{"service": "example-operator", "namespace": "examplep"}},
+			expected:   "examplep",
+		},
+		{
+			service:    "saas-backplane-api",
+			configured: []map[string]string{{"service": "backplane-api", "namespace": "backplanep01"}},
+			expected:   "backplanep01",
+		},
+	}
+
+	for _, test := range tests {
+		viper.Set(ProductionPatternsConfigKey, test.configured)
+		actual, err := ProductionNamespacePattern(test.service)
+		if err != nil {
+			t.Errorf("Test '%s' failed. Unexpected error: %v", test.service, err)
+			continue
+		}
+		if actual.String() != test.expected {
+			t.Errorf("Test '%s' failed. Expected %s, got %s", test.service, test.expected, actual)
+		}
+	}
+
+	viper.Set(ProductionPatternsConfigKey, []map[string]string{{"service": "(", "namespace": "examplep"}})
+	if _, err := ProductionNamespacePattern("saas-example-operator"); err == nil {
+		t.Errorf("Expected an error for an invalid service pattern in %s", ProductionPatternsConfigKey)
+	}
+	viper.Reset()
+}
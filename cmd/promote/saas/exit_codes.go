@@ -0,0 +1,55 @@
+package saas
+
+import (
+	"errors"
+
+	"github.com/openshift/osdctl/cmd/promote/git"
+)
+
+// Exit codes of the SaaS promotion commands. Any failure not listed here exits with 1
+const (
+	// ExitCodeNoGitHash is returned when no git hash could be found to promote
+	ExitCodeNoGitHash = 6
+	// ExitCodeInvalidSaasFile is returned when the SAAS file of the service is invalid
+	ExitCodeInvalidSaasFile = 7
+)
+
+const exitCodesHelp = `
+
+Exit codes:
+  0  the promotion commit is ready, or there is nothing to promote
+  1  the promotion failed
+  6  no git hash could be found to promote
+  7  the SAAS file of the service is invalid`
+
+var errNoGitHash = errors.New("unable to find a git hash to promote")
+
+// exitError sets the exit code osdctl exits with when returned from a command
+type exitError struct {
+	code int
+	err  error
+}
+
+func (e *exitError) Error() string {
+	return e.err.Error()
+}
+
+func (e *exitError) Unwrap() error {
+	return e.err
+}
+
+// ExitCode returns the exit code of the failure
+func (e *exitError) ExitCode() int {
+	return e.code
+}
+
+// withExitCode maps the failures of a promotion to their documented exit code
+func withExitCode(err error) error {
+	if errors.Is(err, errNoGitHash) {
+		return &exitError{code: ExitCodeNoGitHash, err: err}
+	}
+	if errors.Is(err, git.ErrInvalidSaasFile) {
+		return &exitError{code: ExitCodeInvalidSaasFile, err: err}
+	}
+	return err
+}
@@ -0,0 +1,55 @@
+package saas
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/openshift/osdctl/cmd/promote/git"
+)
+
+func TestWithExitCode(t *testing.T) {
+	err := withExitCode(fmt.Errorf("error while promoting service: %w", errNoGitHash))
+	var exitErr interface{ ExitCode() int }
+	if !errors.As(err, &exitErr) || exitErr.ExitCode() != ExitCodeNoGitHash {
+		t.Errorf("expected exit code %d for a missing git hash, got %v", ExitCodeNoGitHash, err)
+	}
+
+	_, _, err = git.GetCurrentGitHashFromAppInterface([]byte("resourceTemplates: ["), "saas-example-operator", nil)
+	err = withExitCode(fmt.Errorf("failed to get current git hash or service repo: %w", err))
+	if !errors.As(err, &exitErr) || exitErr.ExitCode() != ExitCodeInvalidSaasFile {
+		t.Errorf("expected exit code %d for an invalid SAAS file, got %v", ExitCodeInvalidSaasFile, err)
+	}
+
+	err = withExitCode(errors.New("failed to read SAAS file"))
+	if errors.As(err, &exitErr) {
+		t.Errorf("expected no exit code for a generic failure, got %d", exitErr.ExitCode())
+	}
+
+	if withExitCode(nil) != nil {
+		t.Errorf("expected no error")
+	}
+}
+
+func TestServicePromotionInvalidSaasFileExitCode(t *testing.T) {
+	appInterface := git.AppInterface{GitDirectory: t.TempDir()}
+	saasDir := filepath.Join(appInterface.GitDirectory, OSDSaasDir)
+	err := os.MkdirAll(saasDir, 0700)
+	if err != nil {
+		t.Fatalf("failed to create SAAS directory: %v", err)
+	}
+	err = os.WriteFile(filepath.Join(saasDir, "saas-invalid-operator.yaml"), []byte("resourceTemplates: ["), 0600)
+	if err != nil {
+		t.Fatalf("failed to create SAAS file: %v", err)
+	}
+
+	ops := &saasOptions{serviceName: "saas-invalid-operator", osd: true, validateOnly: true, saasDirs: []string{OSDSaasDir}}
+	_, err = servicePromotion(appInterface, ops)
+	err = withExitCode(fmt.Errorf("error while promoting service: %w", err))
+	var exitErr interface{ ExitCode() int }
+	if !errors.As(err, &exitErr) || exitErr.ExitCode() != ExitCodeInvalidSaasFile {
+		t.Errorf("expected exit code %d for an invalid SAAS file, got %v", ExitCodeInvalidSaasFile, err)
+	}
+}
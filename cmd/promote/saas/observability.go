@@ -0,0 +1,25 @@
+package saas
+
+import (
+	"regexp"
+
+	"github.com/spf13/cobra"
+)
+
+// observabilityServicePattern matches the services of ObservabilitySaasDir holding observability configuration
+var observabilityServicePattern = regexp.MustCompile(`observability|rhobs|dynatrace`)
+
+// NewCmdObservability implements the observability command to promote observability configuration, such as Dynatrace and RHOBS
+func NewCmdObservability() *cobra.Command {
+	return newCmdSaasPromotion("observability", "Utilities to promote observability configuration", observabilityExample, []string{ObservabilitySaasDir}, observabilityServicePattern)
+}
+
+const observabilityExample = `
+		# List all observability configuration
+		osdctl promote observability --list
+
+		# Show the targets observability configuration deploys to
+		osdctl promote observability --describe <service-name>
+
+		# Promote observability configuration
+		osdctl promote observability --serviceName <service-name> --gitHash <git-hash> --osd`
@@ -0,0 +1,91 @@
+package saas
+
+import (
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/openshift/osdctl/cmd/promote/git"
+)
+
+func TestObservabilityServices(t *testing.T) {
+	appInterface := git.AppInterface{GitDirectory: "testdata/app-interface"}
+	services, err := GetServiceNames(appInterface, ObservabilitySaasDir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []string{"saas-observability-operator", "saas-rhobs-rules-and-dashboards-production"}
+	if actual := filterServices(services, observabilityServicePattern); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected the observability services %v, got %v", expected, actual)
+	}
+	if err := checkServicePattern("saas-yml-operator", observabilityServicePattern); err == nil {
+		t.Errorf("expected an error for a service that is not observability configuration")
+	}
+}
+
+func TestObservabilityProductionTargets(t *testing.T) {
+	appInterface := git.AppInterface{GitDirectory: "testdata/app-interface"}
+	_, err := GetServiceNames(appInterface, ObservabilitySaasDir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		service            string
+		expectedNamespaces []string
+		expectedRef        string
+	}{
+		{
+			service: "saas-observability-operator",
+			expectedNamespaces: []string{
+				"/services/osd-operators/namespaces/hivep01ue1/cluster-scope.yml",
+				"/services/osd-operators/namespaces/hivep02ue1/cluster-scope.yml",
+			},
+			expectedRef: "6666666666666666666666666666666666666666",
+		},
+		{
+			service: "saas-rhobs-rules-and-dashboards-production",
+			expectedNamespaces: []string{
+				"/services/rhobs/observatorium-mst/namespaces/rhobsp02ue1/observatorium-mst-rules.yml",
+				"/services/rhobs/observatorium-mst/namespaces/rhobsp02ue1/observatorium-mst-dashboards.yml",
+			},
+			expectedRef: "7777777777777777777777777777777777777777",
+		},
+	}
+
+	for _, test := range tests {
+		saasFile, err := GetSaasDir(test.service, true, false)
+		if err != nil {
+			t.Errorf("Test '%s' failed. Unexpected error: %v", test.service, err)
+			continue
+		}
+		serviceData, err := os.ReadFile(saasFile)
+		if err != nil {
+			t.Fatalf("Test '%s' failed. Failed to read %s: %v", test.service, saasFile, err)
+		}
+
+		// No --production-pattern, so the production targets of the service are used
+		namespacePattern, err := ProductionNamespacePattern(test.service)
+		if err != nil {
+			t.Fatalf("Test '%s' failed. Unexpected error: %v", test.service, err)
+		}
+		targets, _, err := git.GetCurrentGitHashFromAppInterface(serviceData, test.service, namespacePattern)
+		if err != nil {
+			t.Errorf("Test '%s' failed. Unexpected error: %v", test.service, err)
+			continue
+		}
+		var namespaces []string
+		for _, target := range targets {
+			namespaces = append(namespaces, target.Namespace)
+		}
+		if !reflect.DeepEqual(namespaces, test.expectedNamespaces) {
+			t.Errorf("Test '%s' failed. Expected the targets %v, got %v", test.service, test.expectedNamespaces, namespaces)
+			continue
+		}
+		ref, err := git.TargetsRef(targets)
+		if err != nil || ref != test.expectedRef {
+			t.Errorf("Test '%s' failed. Expected the targets to be at %s, got %s: %v", test.service, test.expectedRef, ref, err)
+		}
+	}
+}
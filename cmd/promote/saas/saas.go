@@ -2,86 +2,268 @@ package saas
 
 import (
 	"fmt"
-	"os"
+	"regexp"
+	"strings"
+	"time"
 
 	"github.com/openshift/osdctl/cmd/promote/git"
 	"github.com/spf13/cobra"
 )
 
 type saasOptions struct {
-	list bool
-	osd  bool
-	hcp  bool
+	list           bool
+	osd            bool
+	hcp            bool
+	allowDirty     bool
+	force          bool
+	open           bool
+	validateOnly   bool
+	signoff        bool
+	allowDowngrade bool
 
 	appInterfaceCheckoutDir string
 	serviceName             string
+	describe                string
+	fromFile                string
+	revertTo                string
 	gitHash                 string
+	back                    int
 	namespaceRef            string
+	namespace               string
+	productionPattern       string
+	messageTemplate         string
+	author                  string
+	jira                    string
+	repoTimeout             time.Duration
+
+	// saasDirs are the app-interface directories searched for SAAS files
+	saasDirs []string
+	// servicePattern restricts the services of saasDirs handled by the command when set
+	servicePattern *regexp.Regexp
 }
 
 // newCmdSaas implementes the saas command to interact with promoting SaaS services/operators
 func NewCmdSaas() *cobra.Command {
-	ops := &saasOptions{}
-	saasCmd := &cobra.Command{
-		Use:               "saas",
-		Short:             "Utilities to promote SaaS services/operators",
-		Args:              cobra.NoArgs,
-		DisableAutoGenTag: true,
-		Example: `
+	return newCmdSaasPromotion("saas", "Utilities to promote SaaS services/operators", saasExample, ConfiguredSaasDirs(), nil)
+}
+
+const saasExample = `
 		# List all SaaS services/operators
 		osdctl promote saas --list
 
+		# Show how many commits a SaaS service/operator is behind in production
+		osdctl promote saas status <service-name>
+
+		# Show the targets a SaaS service/operator deploys to
+		osdctl promote saas --describe <service-name>
+
 		# Promote a SaaS service/operator
 		osdctl promote saas --serviceName <service-name> --gitHash <git-hash> --osd
 		or
-		osdctl promote saas --serviceName <service-name> --gitHash <git-hash> --hcp`,
-		Run: func(cmd *cobra.Command, args []string) {
-			ops.validateSaasFlow()
-			appInterface := git.BootstrapOsdCtlForAppInterfaceAndServicePromotions(ops.appInterfaceCheckoutDir)
-
-			if ops.list {
-				if ops.serviceName != "" || ops.gitHash != "" || ops.osd || ops.hcp {
-					fmt.Printf("Error: --list cannot be used with any other flags\n\n")
-					cmd.Help()
-					os.Exit(1)
-				}
-				listServiceNames(appInterface)
-				os.Exit(0)
-			}
-
-			if !(ops.osd || ops.hcp) && ops.serviceName != "" {
-				fmt.Printf("Error: --serviceName cannot be used without either --osd or --hcp\n\n")
-				cmd.Help()
-				os.Exit(1)
-			}
-
-			err := servicePromotion(appInterface, ops.serviceName, ops.gitHash, ops.namespaceRef, ops.osd, ops.hcp)
-			if err != nil {
-				fmt.Printf("Error while promoting service: %v\n", err)
-				os.Exit(1)
-			}
-
-			os.Exit(0)
+		osdctl promote saas --serviceName <service-name> --gitHash <git-hash> --hcp
+
+		# Check a promotion would succeed without creating a branch or commit
+		osdctl promote saas --serviceName <service-name> --gitHash <git-hash> --osd --validate-only
+		or
+		osdctl promote --dry-run saas --serviceName <service-name> --gitHash <git-hash> --osd
+
+		# Restore the git hash a SaaS service/operator was at in an earlier app-interface commit
+		osdctl promote saas --serviceName <service-name> --revert-to <app-interface-commit> --osd
+
+		# Promote a single target namespace of a SaaS service/operator
+		osdctl promote saas --serviceName <service-name> --gitHash <git-hash> --osd --namespace hivep01ue1/<namespace>
+
+		# Promote every service listed in a file of 'service=hash' lines
+		osdctl promote saas --from-file promotions.txt --osd
+
+		# Roll a SaaS service/operator back by 2 commits from its current production hash
+		osdctl promote saas --serviceName <service-name> --back 2 --osd
 
+		# Promote a SaaS service/operator using a custom commit message
+		osdctl promote saas --serviceName <service-name> --gitHash <git-hash> --osd --message-template "[JIRA-123] Promote {{.Service}} from {{.FromHash}} to {{.ToHash}}"`
+
+// newCmdSaasPromotion builds a command promoting the SAAS files found in the given app-interface directories. When
+// servicePattern is set, only the services matching it are listed and promoted
+func newCmdSaasPromotion(use, short, example string, saasDirs []string, servicePattern *regexp.Regexp) *cobra.Command {
+	ops := &saasOptions{saasDirs: saasDirs, servicePattern: servicePattern}
+	saasCmd := &cobra.Command{
+		Use:               use,
+		Short:             short,
+		Long:              short + exitCodesHelp,
+		Args:              cobra.NoArgs,
+		DisableAutoGenTag: true,
+		Example:           example,
+		SilenceUsage:      true,
+		SilenceErrors:     true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return withExitCode(ops.run(cmd))
 		},
 	}
 
 	saasCmd.Flags().BoolVarP(&ops.list, "list", "l", false, "List all SaaS services/operators")
 	saasCmd.Flags().StringVarP(&ops.serviceName, "serviceName", "", "", "SaaS service/operator getting promoted")
+	saasCmd.Flags().StringVarP(&ops.describe, "describe", "", "", "Show every target namespace, environment and current ref of the given SaaS service/operator. Use with --hcp for HyperShift deployments")
+	saasCmd.Flags().StringVarP(&ops.fromFile, "from-file", "", "", "Promote every service listed in the given file, one 'service=hash' entry per line. Use with --osd or --hcp")
 	saasCmd.Flags().StringVarP(&ops.gitHash, "gitHash", "g", "", "Git hash of the SaaS service/operator commit getting promoted")
+	saasCmd.Flags().IntVarP(&ops.back, "back", "", 0, "Promote the commit N steps before the currently promoted git hash instead of a specific --gitHash")
+	saasCmd.Flags().StringVarP(&ops.revertTo, "revert-to", "", "", "Promote the git hash the service was at in the given app-interface commit instead of a specific --gitHash")
 	saasCmd.Flags().StringVarP(&ops.namespaceRef, "namespaceRef", "n", "", "SaaS target namespace reference name")
+	saasCmd.Flags().StringVarP(&ops.namespace, "namespace", "", "", "Only promote the target of the given namespace, i.e. hivep01ue1/example-operator or its full $ref")
+	saasCmd.Flags().StringVarP(&ops.productionPattern, "production-pattern", "", "", "Regular expression matching the namespace $ref of the production target. Defaults to the production namespaces of the service, i.e. '"+git.DefaultProductionNamespacePattern+"' for most services, see '"+ProductionPatternsConfigKey+"' in the osdctl config file. Ignored when --namespaceRef is set")
 	saasCmd.Flags().BoolVarP(&ops.osd, "osd", "", false, "OSD service/operator getting promoted")
 	saasCmd.Flags().BoolVarP(&ops.hcp, "hcp", "", false, "HCP service/operator getting promoted")
-	saasCmd.Flags().StringVarP(&ops.appInterfaceCheckoutDir, "appInterfaceDir", "", "", "location of app-interfache checkout. Falls back to `pwd` and "+git.DefaultAppInterfaceDirectory())
+	saasCmd.Flags().BoolVarP(&ops.force, "force", "f", false, "Promote all production targets even if they are currently at different refs, and even if the promotion branch already exists on the origin remote")
+	saasCmd.Flags().BoolVarP(&ops.open, "open", "", false, "Open the merge request creation page in the browser once the promotion commit is ready")
+	saasCmd.Flags().BoolVarP(&ops.validateOnly, "validate-only", "", false, "Only validate the service name, SAAS file and git hash of the promotion without creating a branch or commit in app-interface")
+	saasCmd.Flags().BoolVarP(&ops.allowDowngrade, "allow-downgrade", "", false, "Allow promoting a git hash that is behind the currently promoted one. Not needed with --back")
+	saasCmd.Flags().BoolVarP(&ops.allowDirty, "allow-dirty", "", false, "Allow promoting from an app-interface checkout with uncommitted changes")
+	saasCmd.Flags().StringVarP(&ops.messageTemplate, "message-template", "m", "", "Go template used for the promotion commit message. Supports {{.Service}}, {{.FromHash}}, {{.ToHash}}, {{.CompareURL}}, {{.Jira}} and {{.CommitLog}}. Defaults to 'Promote <service> to <hash>' with a compare link and commit log")
+	saasCmd.Flags().StringVarP(&ops.jira, "jira", "", "", "Jira issue key (i.e. OSD-1234) to reference in the promotion commit")
+	saasCmd.Flags().BoolVarP(&ops.signoff, "signoff", "s", false, "Add a Signed-off-by trailer of the configured git identity to the promotion commit")
+	saasCmd.Flags().StringVarP(&ops.author, "author", "", "", "Override the promotion commit author, in the form 'Name <email>'. Defaults to the git config of the app-interface checkout")
+	saasCmd.Flags().DurationVarP(&ops.repoTimeout, "repo-timeout", "", 30*time.Second, "Timeout for checking the service repo is reachable before cloning it")
+	saasCmd.Flags().StringVarP(&ops.appInterfaceCheckoutDir, "appInterfaceDir", "", "", "location of app-interfache checkout. Falls back to $"+git.AppInterfacePathEnv+", `pwd` and "+git.DefaultAppInterfaceDirectory())
+	_ = saasCmd.RegisterFlagCompletionFunc("serviceName", serviceNameCompletion(&ops.appInterfaceCheckoutDir, ops.servicePattern, ops.saasDirs...))
+	_ = saasCmd.RegisterFlagCompletionFunc("describe", serviceNameCompletion(&ops.appInterfaceCheckoutDir, ops.servicePattern, ops.saasDirs...))
+	saasCmd.MarkFlagsMutuallyExclusive("gitHash", "back", "revert-to")
+	saasCmd.MarkFlagsMutuallyExclusive("list", "describe")
+	saasCmd.MarkFlagsMutuallyExclusive("namespace", "namespaceRef")
+	saasCmd.MarkFlagsMutuallyExclusive("from-file", "serviceName")
+	saasCmd.MarkFlagsMutuallyExclusive("from-file", "gitHash")
+	saasCmd.MarkFlagsMutuallyExclusive("from-file", "back")
+	saasCmd.MarkFlagsMutuallyExclusive("from-file", "revert-to")
+	saasCmd.MarkFlagsMutuallyExclusive("from-file", "describe")
+	saasCmd.MarkFlagsMutuallyExclusive("from-file", "list")
+	saasCmd.MarkFlagsMutuallyExclusive("validate-only", "open")
+	saasCmd.MarkFlagsMutuallyExclusive("serviceName", "describe")
+
+	saasCmd.AddCommand(newCmdStatus(ops.saasDirs, ops.servicePattern))
 
 	return saasCmd
 }
 
+// run validates the options and runs the requested listing, description or promotion
+func (o *saasOptions) run(cmd *cobra.Command) error {
+	o.validateSaasFlow()
+	if err := validateCommitMessageTemplate(o.messageTemplate); err != nil {
+		return err
+	}
+	if _, err := o.targetNamespacePattern(o.serviceName); err != nil {
+		return err
+	}
+	if err := validateJiraKey(o.jira); err != nil {
+		return err
+	}
+	if err := git.ValidateCommitAuthor(o.author); err != nil {
+		return err
+	}
+	appInterface, err := git.FindAppInterface(o.appInterfaceCheckoutDir)
+	if err != nil {
+		return err
+	}
+	appInterface.DryRun, _ = cmd.Flags().GetBool(git.DryRunFlag)
+
+	if o.list {
+		if o.serviceName != "" || o.gitHash != "" || o.back != 0 || o.osd || o.hcp {
+			_ = cmd.Help()
+			return fmt.Errorf("--list cannot be used with any other flags")
+		}
+		return listServiceNames(appInterface, o.servicePattern, o.saasDirs...)
+	}
+
+	for _, serviceName := range []string{o.serviceName, o.describe} {
+		if err := checkServicePattern(serviceName, o.servicePattern); err != nil {
+			return err
+		}
+	}
+
+	if o.describe != "" {
+		err := describeService(appInterface, o.saasDirs, o.describe, o.hcp)
+		if err != nil {
+			return fmt.Errorf("error while describing service: %w", err)
+		}
+		return nil
+	}
+
+	if o.back < 0 {
+		_ = cmd.Help()
+		return fmt.Errorf("--back must be a positive number of commits")
+	}
+
+	if !(o.osd || o.hcp) && o.serviceName != "" {
+		_ = cmd.Help()
+		return fmt.Errorf("--serviceName cannot be used without either --osd or --hcp")
+	}
+
+	if o.fromFile != "" {
+		if !(o.osd || o.hcp) {
+			_ = cmd.Help()
+			return fmt.Errorf("--from-file cannot be used without either --osd or --hcp")
+		}
+		return batchPromotion(appInterface, o)
+	}
+
+	result, err := servicePromotion(appInterface, o)
+	if err != nil {
+		return fmt.Errorf("error while promoting service: %w", err)
+	}
+
+	printPromotionResult(result)
+	if o.open && result.MergeRequestURL != "" {
+		openURL(result.MergeRequestURL)
+	}
+	return nil
+}
+
 func (o *saasOptions) validateSaasFlow() {
-	if o.serviceName == "" && o.gitHash == "" {
+	if o.serviceName == "" && o.gitHash == "" && o.describe == "" && o.fromFile == "" {
 		fmt.Printf("Usage: For SaaS services/operators, please provide --serviceName and (optional) --gitHash\n")
 		fmt.Printf("--serviceName is the name of the service, i.e. saas-managed-cluster-config\n")
 		fmt.Printf("--gitHash is the target git commit in the service, if not specified defaults to HEAD of master\n\n")
 		return
 	}
 }
+
+// targetNamespacePattern returns the pattern used to select the target being promoted. An explicit
+// --namespace or --namespaceRef takes precedence over the production pattern. Without any of them the production
+// targets of the service are selected, see ProductionNamespacePattern
+func (o *saasOptions) targetNamespacePattern(serviceName string) (*regexp.Regexp, error) {
+	if o.namespace != "" {
+		// Match the namespace file referenced by the target, with or without its extension
+		return regexp.MustCompile(`(^|/)` + regexp.QuoteMeta(strings.Trim(o.namespace, "/")) + `(\.ya?ml)?$`), nil
+	}
+	if o.namespaceRef != "" {
+		return regexp.MustCompile(regexp.QuoteMeta(o.namespaceRef)), nil
+	}
+
+	if o.productionPattern == "" {
+		return ProductionNamespacePattern(serviceName)
+	}
+	pattern, err := regexp.Compile(o.productionPattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --production-pattern '%s': %w", o.productionPattern, err)
+	}
+	return pattern, nil
+}
+
+// ServiceNameCompletion completes service names from the SAAS files in the given directories of the app-interface checkout.
+// Nothing is completed when no app-interface checkout can be found.
+func ServiceNameCompletion(appInterfaceCheckoutDir *string, saasDirs ...string) func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return serviceNameCompletion(appInterfaceCheckoutDir, nil, saasDirs...)
+}
+
+// serviceNameCompletion completes the service names matching servicePattern, or all of them when it is nil
+func serviceNameCompletion(appInterfaceCheckoutDir *string, servicePattern *regexp.Regexp, saasDirs ...string) func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return func(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+		appInterface, err := git.FindAppInterface(*appInterfaceCheckoutDir)
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		services, err := GetServiceNames(appInterface, saasDirs...)
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return filterServices(services, servicePattern), cobra.ShellCompDirectiveNoFileComp
+	}
+}
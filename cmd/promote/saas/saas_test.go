@@ -0,0 +1,61 @@
+package saas
+
+import (
+	"testing"
+
+	"github.com/openshift/osdctl/cmd/promote/git"
+)
+
+func TestTargetNamespacePattern(t *testing.T) {
+	tests := []struct {
+		name     string
+		ops      saasOptions
+		matches  []string
+		excludes []string
+	}{
+		{
+			name:     "production pattern",
+			ops:      saasOptions{productionPattern: git.DefaultProductionNamespacePattern},
+			matches:  []string{"/services/osd-operators/namespaces/hivep01ue1/example-operator.yml"},
+			excludes: []string{"/services/osd-operators/namespaces/hivei01ue1/example-operator.yml"},
+		},
+		{
+			name:    "namespace",
+			ops:     saasOptions{productionPattern: git.DefaultProductionNamespacePattern, namespace: "hivep02ue1/example-operator"},
+			matches: []string{"/services/osd-operators/namespaces/hivep02ue1/example-operator.yml"},
+			excludes: []string{
+				"/services/osd-operators/namespaces/hivep01ue1/example-operator.yml",
+				"/services/osd-operators/namespaces/hivep02ue1/example-operator-extra.yml",
+			},
+		},
+		{
+			name:     "namespace with its full ref",
+			ops:      saasOptions{namespace: "/services/osd-operators/namespaces/hivep02ue1/example-operator.yml"},
+			matches:  []string{"/services/osd-operators/namespaces/hivep02ue1/example-operator.yml"},
+			excludes: []string{"/services/osd-operators/namespaces/hivep01ue1/example-operator.yml"},
+		},
+	}
+
+	pattern, err := (&saasOptions{}).targetNamespacePattern("saas-backplane-api")
+	if err != nil || pattern.String() != "backplanep" {
+		t.Errorf("expected the production pattern of the service without --production-pattern, --namespace or --namespaceRef, got %v: %v", pattern, err)
+	}
+
+	for _, test := range tests {
+		pattern, err := test.ops.targetNamespacePattern("saas-example-operator")
+		if err != nil {
+			t.Errorf("Test '%s' failed. Unexpected error: %v", test.name, err)
+			continue
+		}
+		for _, ref := range test.matches {
+			if !pattern.MatchString(ref) {
+				t.Errorf("Test '%s' failed. Expected %s to match", test.name, ref)
+			}
+		}
+		for _, ref := range test.excludes {
+			if pattern.MatchString(ref) {
+				t.Errorf("Test '%s' failed. Expected %s not to match", test.name, ref)
+			}
+		}
+	}
+}
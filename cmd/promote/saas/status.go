@@ -0,0 +1,136 @@
+package saas
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+
+	"github.com/openshift/osdctl/cmd/promote/git"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// statusOptions defines the options of the status subcommand
+type statusOptions struct {
+	hcp                     bool
+	productionPattern       string
+	appInterfaceCheckoutDir string
+
+	// saasDirs are the app-interface directories searched for SAAS files
+	saasDirs []string
+	// servicePattern restricts the services of saasDirs handled by the command when set
+	servicePattern *regexp.Regexp
+}
+
+// ServiceStatus describes how far the production deployment of a service is behind its repository
+type ServiceStatus struct {
+	Service      string `json:"service"`
+	SaasFile     string `json:"saasFile"`
+	CurrentHash  string `json:"currentHash"`
+	HeadHash     string `json:"headHash"`
+	CommitsAhead int    `json:"commitsAhead"`
+	CompareURL   string `json:"compareURL"`
+}
+
+// newCmdStatus implements the read-only status subcommand, showing how stale the production deployment of a service is
+func newCmdStatus(saasDirs []string, servicePattern *regexp.Regexp) *cobra.Command {
+	ops := &statusOptions{saasDirs: saasDirs, servicePattern: servicePattern}
+	statusCmd := &cobra.Command{
+		Use:               "status <service-name>",
+		Short:             "Show how many commits the production deployment of a service is behind its repository",
+		Args:              cobra.ExactArgs(1),
+		DisableAutoGenTag: true,
+		SilenceUsage:      true,
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if len(args) > 0 {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			}
+			return serviceNameCompletion(&ops.appInterfaceCheckoutDir, ops.servicePattern, ops.saasDirs...)(cmd, args, toComplete)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := checkServicePattern(args[0], ops.servicePattern); err != nil {
+				return err
+			}
+			appInterface, err := git.FindAppInterface(ops.appInterfaceCheckoutDir)
+			if err != nil {
+				return err
+			}
+
+			status, err := serviceStatus(appInterface, ops, args[0])
+			if err != nil {
+				return err
+			}
+			printServiceStatus(status)
+			return nil
+		},
+	}
+
+	statusCmd.Flags().BoolVarP(&ops.hcp, "hcp", "", false, "Show the HyperShift deployment of the service instead of the OSD one")
+	statusCmd.Flags().StringVarP(&ops.productionPattern, "production-pattern", "", "", "Regular expression matching the namespace $ref of the production target. Defaults to the production namespaces of the service")
+	statusCmd.Flags().StringVarP(&ops.appInterfaceCheckoutDir, "appInterfaceDir", "", "", "location of app-interfache checkout. Falls back to $"+git.AppInterfacePathEnv+", `pwd` and "+git.DefaultAppInterfaceDirectory())
+	return statusCmd
+}
+
+// serviceStatus compares the git hash the service is deployed at in production against the HEAD of its repository.
+// Nothing is changed in app-interface
+func serviceStatus(appInterface git.AppInterface, ops *statusOptions, serviceName string) (*ServiceStatus, error) {
+	_, err := GetServiceNames(appInterface, ops.saasDirs...)
+	if err != nil {
+		return nil, err
+	}
+
+	serviceName, err = ValidateServiceName(ServicesSlice, serviceName)
+	if err != nil {
+		return nil, err
+	}
+
+	saasDir, err := GetSaasDir(serviceName, !ops.hcp, ops.hcp)
+	if err != nil {
+		return nil, err
+	}
+
+	serviceData, err := os.ReadFile(saasDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read SAAS file: %v", err)
+	}
+
+	namespacePattern, err := (&saasOptions{productionPattern: ops.productionPattern}).targetNamespacePattern(serviceName)
+	if err != nil {
+		return nil, err
+	}
+
+	targets, serviceRepo, err := git.GetCurrentGitHashFromAppInterface(serviceData, serviceName, namespacePattern)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current git hash or service repo: %w", err)
+	}
+
+	currentGitHash, err := git.TargetsRef(targets)
+	if err != nil {
+		log.Warnf("%v. Comparing against %s", err, currentGitHash)
+	}
+
+	headHash, commitsAhead, err := git.CommitsAhead(serviceRepo, currentGitHash)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ServiceStatus{
+		Service:      serviceName,
+		SaasFile:     saasDir,
+		CurrentHash:  currentGitHash,
+		HeadHash:     headHash,
+		CommitsAhead: commitsAhead,
+		CompareURL:   git.CompareURL(serviceRepo, currentGitHash, headHash),
+	}, nil
+}
+
+func printServiceStatus(status *ServiceStatus) {
+	fmt.Println("service:", status.Service)
+	fmt.Println("saas file:", status.SaasFile)
+	fmt.Println("production:", status.CurrentHash)
+	fmt.Println("head:", status.HeadHash)
+	fmt.Println("commits behind:", status.CommitsAhead)
+	if status.CommitsAhead > 0 {
+		fmt.Println("compare:", status.CompareURL)
+	}
+}
@@ -1,43 +1,89 @@
 package saas
 
 import (
+	"bytes"
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
+	"runtime"
 	"sort"
 	"strings"
+	"text/template"
 
 	"github.com/openshift/osdctl/cmd/promote/git"
+	"github.com/openshift/osdctl/pkg/printer"
+	"github.com/openshift/osdctl/pkg/utils"
+	"github.com/pkg/browser"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/sync/errgroup"
 )
 
 const (
 	OSDSaasDir = "data/services/osd-operators/cicd/saas"
 	BPSaasDir  = "data/services/backplane/cicd/saas"
 	CADSaasDir = "data/services/configuration-anomaly-detection/cicd"
+
+	// Observability configuration, such as saas-observability-operator and the rhobs-rules-and-dashboards SAAS files,
+	// is deployed from the OSD operators directory rather than a directory of its own
+	ObservabilitySaasDir = OSDSaasDir
+	// Package-operator services, whose SAAS files deploy a PACKAGE_TAG, are OSD operators as well
+	PackageSaasDir = OSDSaasDir
 )
 
+// saasFileExtensions are the extensions of SAAS files, in order of preference
+var saasFileExtensions = []string{".yaml", ".yml"}
+
 var (
 	ServicesSlice    []string
 	ServicesFilesMap = map[string]string{}
+
+	// listedSaasDirs holds the SAAS directories already added to ServicesSlice and ServicesFilesMap, so they are
+	// only globbed once per run
+	listedSaasDirs = map[string]bool{}
 )
 
-func listServiceNames(appInterface git.AppInterface) error {
-	_, err := GetServiceNames(appInterface, OSDSaasDir, BPSaasDir, CADSaasDir)
+func listServiceNames(appInterface git.AppInterface, servicePattern *regexp.Regexp, saasDirs ...string) error {
+	_, err := GetServiceNames(appInterface, saasDirs...)
 	if err != nil {
 		return err
 	}
 
 	sort.Strings(ServicesSlice)
 	fmt.Println("### Available service names ###")
-	for _, service := range ServicesSlice {
+	for _, service := range filterServices(ServicesSlice, servicePattern) {
 		fmt.Println(service)
 	}
 
 	return nil
 }
 
-func servicePromotion(appInterface git.AppInterface, serviceName, gitHash string, namespaceRef string, osd, hcp bool) error {
-	_, err := GetServiceNames(appInterface, OSDSaasDir, BPSaasDir, CADSaasDir)
+// filterServices returns the services matching the pattern, or all of them when it is nil
+func filterServices(services []string, servicePattern *regexp.Regexp) []string {
+	if servicePattern == nil {
+		return services
+	}
+	var filtered []string
+	for _, service := range services {
+		if servicePattern.MatchString(service) {
+			filtered = append(filtered, service)
+		}
+	}
+	return filtered
+}
+
+// checkServicePattern ensures the service belongs to the command, i.e. that promote observability is not used to
+// promote any other service of the OSD operators directory
+func checkServicePattern(serviceName string, servicePattern *regexp.Regexp) error {
+	if serviceName == "" || servicePattern == nil || servicePattern.MatchString(serviceName) {
+		return nil
+	}
+	return fmt.Errorf("service %s is not handled by this command, run it with --list to see the available services", serviceName)
+}
+
+// describeService prints every target of the service's SAAS file along with the environment and ref it's deployed at
+func describeService(appInterface git.AppInterface, saasDirs []string, serviceName string, hcp bool) error {
+	_, err := GetServiceNames(appInterface, saasDirs...)
 	if err != nil {
 		return err
 	}
@@ -47,73 +93,431 @@ func servicePromotion(appInterface git.AppInterface, serviceName, gitHash string
 		return err
 	}
 
-	saasDir, err := GetSaasDir(serviceName, osd, hcp)
+	saasDir, err := GetSaasDir(serviceName, !hcp, hcp)
 	if err != nil {
 		return err
 	}
-	fmt.Printf("SAAS Directory: %v\n", saasDir)
 
 	serviceData, err := os.ReadFile(saasDir)
 	if err != nil {
 		return fmt.Errorf("failed to read SAAS file: %v", err)
 	}
 
-	currentGitHash, serviceRepo, err := git.GetCurrentGitHashFromAppInterface(serviceData, serviceName, namespaceRef)
+	err = git.ValidateSaasFile(saasDir, serviceData)
+	if err != nil {
+		return err
+	}
+
+	targets, err := git.GetSaasTargets(serviceData)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("SAAS File: %s\n\n", saasDir)
+	table := printer.NewTablePrinter(os.Stdout, 20, 1, 3, ' ')
+	table.AddRow([]string{"RESOURCE TEMPLATE", "NAMESPACE", "ENVIRONMENT", "REF"})
+	for _, target := range targets {
+		environment := appInterface.TargetEnvironment(target.Namespace)
+		if environment == "" {
+			environment = "unknown"
+		}
+		table.AddRow([]string{target.ResourceTemplate, target.Namespace, environment, target.Ref})
+	}
+
+	return table.Flush()
+}
+
+// commitMessageData holds the values available to a user provided commit message template
+type commitMessageData struct {
+	Service    string
+	FromHash   string
+	ToHash     string
+	CompareURL string
+	Jira       string
+	CommitLog  string
+}
+
+var jiraKeyRegex = regexp.MustCompile(`^[A-Z][A-Z0-9]+-[0-9]+$`)
+
+// validateJiraKey ensures the provided Jira issue key looks like 'OSD-1234'
+func validateJiraKey(jiraKey string) error {
+	if jiraKey != "" && !jiraKeyRegex.MatchString(jiraKey) {
+		return fmt.Errorf("invalid Jira issue key '%s', expected the form 'PROJECT-1234'", jiraKey)
+	}
+	return nil
+}
+
+// validateCommitMessageTemplate ensures the provided commit message template can be parsed
+func validateCommitMessageTemplate(messageTemplate string) error {
+	if messageTemplate == "" {
+		return nil
+	}
+	_, err := template.New("commitMessage").Option("missingkey=error").Parse(messageTemplate)
+	if err != nil {
+		return fmt.Errorf("invalid commit message template: %w", err)
+	}
+	return nil
+}
+
+// renderCommitMessage renders the commit message for a promotion, falling back to the default format when no template is provided.
+// When a Jira issue is provided, the default format is prefixed with its key and a link to it is added unless the template already references it.
+func renderCommitMessage(messageTemplate string, data commitMessageData) (string, error) {
+	var message string
+	if messageTemplate == "" {
+		message = fmt.Sprintf("Promote %s to %s\n\nSee %s for contents of the promotion.\n clog:%s", data.Service, data.ToHash, data.CompareURL, data.CommitLog)
+		if data.Jira != "" {
+			message = fmt.Sprintf("[%s] %s", data.Jira, message)
+		}
+	} else {
+		tmpl, err := template.New("commitMessage").Option("missingkey=error").Parse(messageTemplate)
+		if err != nil {
+			return "", fmt.Errorf("invalid commit message template: %w", err)
+		}
+
+		var buf bytes.Buffer
+		err = tmpl.Execute(&buf, data)
+		if err != nil {
+			return "", fmt.Errorf("failed to render commit message template: %w", err)
+		}
+		message = buf.String()
+	}
+
+	if data.Jira != "" && !strings.Contains(message, utils.JiraBaseURL+"/browse/"+data.Jira) {
+		message = fmt.Sprintf("%s\n\nJira: %s/browse/%s", strings.TrimRight(message, "\n"), utils.JiraBaseURL, data.Jira)
+	}
+	return message, nil
+}
+
+// targetChangeRows returns a table row for every target of the SAAS file, showing whether its ref changes from the
+// promotion of the given targets to newRef
+func targetChangeRows(allTargets, promotedTargets []git.SaasTarget, newRef string) [][]string {
+	promoted := map[[2]int]bool{}
+	for _, target := range promotedTargets {
+		promoted[[2]int{target.ResourceTemplateIndex, target.TargetIndex}] = true
+	}
+
+	var rows [][]string
+	for _, target := range allTargets {
+		change := "unchanged"
+		if promoted[[2]int{target.ResourceTemplateIndex, target.TargetIndex}] && target.Ref != newRef {
+			change = fmt.Sprintf("%s -> %s", target.Ref, newRef)
+		}
+		rows = append(rows, []string{target.ResourceTemplate, target.Namespace, change})
+	}
+	return rows
+}
+
+// printTargetChanges prints which targets of the SAAS file have their ref changed by the promotion
+func printTargetChanges(allTargets, promotedTargets []git.SaasTarget, newRef string) error {
+	table := printer.NewTablePrinter(os.Stdout, 20, 1, 3, ' ')
+	table.AddRow([]string{"RESOURCE TEMPLATE", "NAMESPACE", "REF"})
+	for _, row := range targetChangeRows(allTargets, promotedTargets, newRef) {
+		table.AddRow(row)
+	}
+	return table.Flush()
+}
+
+// checkNamespaceTarget returns an error listing the namespaces of the SAAS file when none of its targets match the
+// --namespace pattern
+func checkNamespaceTarget(serviceData []byte, namespace string, namespacePattern *regexp.Regexp) error {
+	targets, err := git.GetSaasTargets(serviceData)
+	if err != nil {
+		return err
+	}
+
+	var namespaces []string
+	for _, target := range targets {
+		if namespacePattern.MatchString(target.Namespace) {
+			return nil
+		}
+		namespaces = append(namespaces, target.Namespace)
+	}
+	return fmt.Errorf("namespace %s is not a target of the SAAS file, available namespaces are:\n  %s", namespace, strings.Join(namespaces, "\n  "))
+}
+
+// revertToRef returns the ref the service's targets were at in the given app-interface commit, once confirmed by the user
+func revertToRef(appInterface git.AppInterface, commit, saasDir, serviceName string, namespacePattern *regexp.Regexp, confirm bool) (string, error) {
+	historicalData, err := appInterface.FileAtCommit(commit, saasDir)
+	if err != nil {
+		return "", err
+	}
+
+	err = git.ValidateSaasFile(saasDir+"@"+commit, historicalData)
+	if err != nil {
+		return "", err
+	}
+
+	targets, _, err := git.GetCurrentGitHashFromAppInterface(historicalData, serviceName, namespacePattern)
+	if err != nil {
+		return "", fmt.Errorf("failed to get the git hash at app-interface commit %s: %w", commit, err)
+	}
+
+	ref, err := git.TargetsRef(targets)
+	if err != nil {
+		return "", fmt.Errorf("targets at app-interface commit %s: %w", commit, err)
+	}
+
+	fmt.Printf("Service %s was at %s in app-interface commit %s\n", serviceName, ref, commit)
+	if confirm && !utils.ConfirmPrompt() {
+		return "", fmt.Errorf("revert of %s to %s aborted", serviceName, ref)
+	}
+	return ref, nil
+}
+
+// PromotionResult describes the outcome of a service promotion. Branch and CommitSHA are only set when a promotion
+// commit was created in app-interface
+type PromotionResult struct {
+	Service         string `json:"service"`
+	SaasFile        string `json:"saasFile"`
+	FromHash        string `json:"fromHash"`
+	ToHash          string `json:"toHash"`
+	CompareURL      string `json:"compareURL,omitempty"`
+	Branch          string `json:"branch,omitempty"`
+	CommitSHA       string `json:"commitSHA,omitempty"`
+	CommitMessage   string `json:"commitMessage,omitempty"`
+	MergeRequestURL string `json:"mergeRequestURL,omitempty"`
+}
+
+// printPromotionResult prints the summary of a promotion
+func printPromotionResult(result *PromotionResult) {
+	if result.CommitSHA == "" {
+		if result.FromHash == result.ToHash {
+			fmt.Printf("Service %s is already at target %s, nothing to promote\n", result.Service, result.ToHash)
+		} else {
+			fmt.Printf("Validation succeeded, %s can be promoted to %s\n", result.Service, result.ToHash)
+		}
+		return
+	}
+
+	fmt.Printf("commitMessage: %s\n", result.CommitMessage)
+
+	fmt.Printf("The branch %s is ready to be pushed\n", result.Branch)
+	fmt.Println("")
+	fmt.Println("service:", result.Service)
+	fmt.Println("from:", result.FromHash)
+	fmt.Println("to:", result.ToHash)
+	fmt.Println("compare:", result.CompareURL)
+	fmt.Println("commit:", result.CommitSHA)
+	fmt.Println("READY TO PUSH,", result.Service, "promotion commit is ready locally")
+	if result.MergeRequestURL != "" {
+		fmt.Printf("Once pushed, create the merge request at: %s\n", result.MergeRequestURL)
+	}
+}
+
+func servicePromotion(appInterface git.AppInterface, ops *saasOptions) (*PromotionResult, error) {
+	// Validating or previewing a promotion never prompts, changes app-interface or contacts its remote
+	preview := ops.validateOnly || appInterface.DryRun
+	if !ops.allowDirty && !preview {
+		err := appInterface.CheckWorkingTreeClean()
+		if err != nil {
+			return nil, fmt.Errorf("%w\nCommit or stash these changes, or pass --allow-dirty to promote anyway", err)
+		}
+	}
+
+	_, err := GetServiceNames(appInterface, ops.saasDirs...)
+	if err != nil {
+		return nil, err
+	}
+
+	serviceName, err := ValidateServiceName(ServicesSlice, ops.serviceName)
+	if err != nil {
+		return nil, err
+	}
+
+	saasDir, err := GetSaasDir(serviceName, ops.osd, ops.hcp)
+	if err != nil {
+		return nil, err
+	}
+	fmt.Printf("SAAS Directory: %v\n", saasDir)
+
+	namespacePattern, err := ops.targetNamespacePattern(serviceName)
+	if err != nil {
+		return nil, err
+	}
+
+	serviceData, err := os.ReadFile(saasDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read SAAS file: %v", err)
+	}
+
+	err = git.ValidateSaasFile(saasDir, serviceData)
+	if err != nil {
+		return nil, err
+	}
+
+	if ops.namespace != "" {
+		err = checkNamespaceTarget(serviceData, ops.namespace, namespacePattern)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	targets, serviceRepo, err := git.GetCurrentGitHashFromAppInterface(serviceData, serviceName, namespacePattern)
 	if err != nil {
-		return fmt.Errorf("failed to get current git hash or service repo: %v", err)
+		return nil, fmt.Errorf("failed to get current git hash or service repo: %w", err)
 	}
+
+	gitHash, allowDowngrade := ops.gitHash, ops.allowDowngrade
+	if ops.revertTo != "" {
+		gitHash, err = revertToRef(appInterface, ops.revertTo, saasDir, serviceName, namespacePattern, !preview)
+		if err != nil {
+			return nil, err
+		}
+		// Reverting to an earlier app-interface state is expected to roll the service back
+		allowDowngrade = true
+	}
+
+	currentGitHash, refsErr := git.TargetsRef(targets)
+	if refsErr != nil {
+		if !ops.force {
+			return nil, fmt.Errorf("%w\nPass --force to promote all of them to the same hash", refsErr)
+		}
+		log.Warnf("%v. Comparing against %s", refsErr, currentGitHash)
+	}
+	// A promotion is only a no-op if every target is already at the target hash
+	targetsAgree := refsErr == nil
 	fmt.Printf("Current Git Hash: %v\nGit Repo: %v\n\n", currentGitHash, serviceRepo)
 
-	promotionGitHash, commitLog, err := git.CheckoutAndCompareGitHash(serviceRepo, gitHash, currentGitHash)
+	result := &PromotionResult{
+		Service:  serviceName,
+		SaasFile: saasDir,
+		FromHash: currentGitHash,
+		ToHash:   currentGitHash,
+	}
+	if targetsAgree && gitHash == currentGitHash {
+		return result, nil
+	}
+
+	err = git.CheckServiceRepoReachable(serviceRepo, ops.repoTimeout)
 	if err != nil {
-		return fmt.Errorf("failed to checkout and compare git hash: %v", err)
+		return nil, err
+	}
+
+	stopCompare := utils.Timings.Start("git clone and compare " + serviceName)
+	promotionGitHash, commitLog, err := git.CheckoutAndCompareGitHash(serviceRepo, gitHash, currentGitHash, ops.back, allowDowngrade, !preview)
+	stopCompare()
+	if err != nil {
+		return nil, fmt.Errorf("failed to checkout and compare git hash: %v", err)
 	} else if promotionGitHash == "" {
-		fmt.Printf("Unable to find a git hash to promote. Exiting.\n")
-		os.Exit(6)
+		return nil, errNoGitHash
+	} else if targetsAgree && promotionGitHash == currentGitHash {
+		return result, nil
 	}
+	result.ToHash = promotionGitHash
+	result.CompareURL = git.CompareURL(serviceRepo, currentGitHash, promotionGitHash)
 	fmt.Printf("Service: %s will be promoted to %s\n", serviceName, promotionGitHash)
+	fmt.Printf("Compare: %s\n", result.CompareURL)
 
+	allTargets, err := git.GetSaasTargets(serviceData)
+	if err != nil {
+		return nil, err
+	}
+	fmt.Println("")
+	err = printTargetChanges(allTargets, targets, promotionGitHash)
 	if err != nil {
-		return fmt.Errorf("error in executing git log: %v", err)
+		return nil, err
 	}
+	fmt.Println("")
+
+	if preview {
+		return result, nil
+	}
+
 	branchName := fmt.Sprintf("promote-%s-%s", serviceName, promotionGitHash)
-	err = appInterface.UpdateAppInterface(serviceName, saasDir, currentGitHash, promotionGitHash, branchName)
+	err = appInterface.CheckPromotionBranch(branchName, ops.force)
 	if err != nil {
-		fmt.Printf("FAILURE: %v\n", err)
+		return nil, err
 	}
 
-	commitMessage := fmt.Sprintf("Promote %s to %s\n\nSee %s/compare/%s...%s for contents of the promotion.\n clog:%s", serviceName, promotionGitHash, serviceRepo, currentGitHash, promotionGitHash, commitLog)
-	err = appInterface.CommitSaasFile(saasDir, commitMessage)
+	err = appInterface.UpdateAppInterface(saasDir, targets, promotionGitHash, branchName)
 	if err != nil {
-		return fmt.Errorf("failed to commit changes to app-interface: %w", err)
+		return nil, fmt.Errorf("failed to update app-interface: %w", err)
 	}
-	fmt.Printf("commitMessage: %s\n", commitMessage)
 
-	fmt.Printf("The branch %s is ready to be pushed\n", branchName)
-	fmt.Println("")
-	fmt.Println("service:", serviceName)
-	fmt.Println("from:", currentGitHash)
-	fmt.Println("to:", promotionGitHash)
-	fmt.Println("READY TO PUSH,", serviceName, "promotion commit is ready locally")
-	return nil
+	commitMessage, err := renderCommitMessage(ops.messageTemplate, commitMessageData{
+		Service:    serviceName,
+		FromHash:   currentGitHash,
+		ToHash:     promotionGitHash,
+		CompareURL: result.CompareURL,
+		Jira:       ops.jira,
+		CommitLog:  commitLog,
+	})
+	if err != nil {
+		return nil, err
+	}
+	commitSHA, err := appInterface.CommitSaasFile(saasDir, commitMessage, ops.author, ops.signoff)
+	if err != nil {
+		return nil, fmt.Errorf("failed to commit changes to app-interface: %w", err)
+	}
+
+	result.Branch = branchName
+	result.CommitSHA = commitSHA
+	result.CommitMessage = commitMessage
+	result.MergeRequestURL = appInterface.MergeRequestURL(branchName)
+	return result, nil
+}
+
+// openURL opens the given URL in the default browser. When no browser can be launched, such as in a headless
+// session, the URL has already been printed for the user so failures are only reported
+func openURL(url string) {
+	if isHeadless() {
+		fmt.Println("No display detected, not opening a browser")
+		return
+	}
+	err := browser.OpenURL(url)
+	if err != nil {
+		fmt.Printf("Unable to open a browser: %v\n", err)
+	}
 }
 
+// isHeadless reports whether the current session has no graphical display to open a browser on
+func isHeadless() bool {
+	switch runtime.GOOS {
+	case "darwin", "windows":
+		return os.Getenv("SSH_CONNECTION") != ""
+	default:
+		return os.Getenv("DISPLAY") == "" && os.Getenv("WAYLAND_DISPLAY") == ""
+	}
+}
+
+// GetServiceNames adds the services of the given SAAS directories to ServicesSlice and ServicesFilesMap and returns
+// the sorted ServicesSlice. The directories are globbed concurrently
 func GetServiceNames(appInterface git.AppInterface, saaDirs ...string) ([]string, error) {
 	baseDir := appInterface.GitDirectory
 
+	var dirs []string
 	for _, dir := range saaDirs {
-		dirGlob := filepath.Join(baseDir, dir, "saas-*")
-		filepaths, err := filepath.Glob(dirGlob)
-		if err != nil {
-			return nil, err
+		if !listedSaasDirs[filepath.Join(baseDir, dir)] {
+			dirs = append(dirs, dir)
 		}
-		for _, filepath := range filepaths {
+	}
+
+	dirsFilepaths := make([][]string, len(dirs))
+	var eg errgroup.Group
+	for i, dir := range dirs {
+		eg.Go(func() error {
+			filepaths, err := filepath.Glob(filepath.Join(baseDir, dir, "saas-*"))
+			if err != nil {
+				return err
+			}
+			dirsFilepaths[i] = filepaths
+			return nil
+		})
+	}
+	if err := eg.Wait(); err != nil {
+		return nil, err
+	}
+
+	for i, dir := range dirs {
+		for _, filepath := range dirsFilepaths[i] {
 			filename := strings.TrimPrefix(filepath, baseDir+"/"+dir+"/")
-			filename = strings.TrimSuffix(filename, ".yaml")
+			filename = trimSaasFileExtension(filename)
 			ServicesSlice = append(ServicesSlice, filename)
 			ServicesFilesMap[filename] = filepath
 		}
+		listedSaasDirs[filepath.Join(baseDir, dir)] = true
 	}
+	sort.Strings(ServicesSlice)
 
 	return ServicesSlice, nil
 }
@@ -136,19 +540,39 @@ func ValidateServiceName(serviceSlice []string, serviceName string) (string, err
 
 func GetSaasDir(serviceName string, osd bool, hcp bool) (string, error) {
 	if saasDir, ok := ServicesFilesMap[serviceName]; ok {
-		if strings.Contains(saasDir, ".yaml") && osd {
+		if trimSaasFileExtension(saasDir) != saasDir && osd {
 			return saasDir, nil
 		}
 
 		// This is a hack while we migrate the rest of the operators unto Progressive Delivery
 		if osd {
-			saasDir = saasDir + "/deploy.yaml"
-			return saasDir, nil
+			return deployFile(saasDir, "deploy"), nil
 		} else if hcp {
-			saasDir = saasDir + "/hypershift-deploy.yaml"
-			return saasDir, nil
+			return deployFile(saasDir, "hypershift-deploy"), nil
 		}
 	}
 
 	return "", fmt.Errorf("saas directory for service %s not found", serviceName)
 }
+
+// trimSaasFileExtension removes the .yaml or .yml extension of a SAAS file
+func trimSaasFileExtension(filename string) string {
+	for _, extension := range saasFileExtensions {
+		if strings.HasSuffix(filename, extension) {
+			return strings.TrimSuffix(filename, extension)
+		}
+	}
+	return filename
+}
+
+// deployFile returns the path of the named deploy file in a SAAS directory, using whichever of the .yaml or .yml
+// extensions exists and defaulting to .yaml
+func deployFile(saasDir, name string) string {
+	for _, extension := range saasFileExtensions {
+		path := filepath.Join(saasDir, name+extension)
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	return filepath.Join(saasDir, name+saasFileExtensions[0])
+}
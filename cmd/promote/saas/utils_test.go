@@ -0,0 +1,182 @@
+package saas
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/openshift/osdctl/cmd/promote/git"
+)
+
+func TestRenderCommitMessage(t *testing.T) {
+	data := commitMessageData{
+		Service:    "saas-example-operator",
+		FromHash:   "abc",
+		ToHash:     "def",
+		CompareURL: "https://github.com/openshift/example-operator/compare/abc...def",
+		CommitLog:  "commit def",
+	}
+	withJira := data
+	withJira.Jira = "OSD-1234"
+
+	tests := []struct {
+		Name           string
+		Template       string
+		Data           commitMessageData
+		ExpectedPrefix string
+		ExpectedSuffix string
+	}{
+		{
+			Name:           "Default format",
+			Data:           data,
+			ExpectedPrefix: "Promote saas-example-operator to def\n\nSee https://github.com/openshift/example-operator/compare/abc...def",
+			ExpectedSuffix: "clog:commit def",
+		},
+		{
+			Name:           "Custom template",
+			Template:       "{{.Service}}: {{.FromHash}} -> {{.ToHash}}",
+			Data:           data,
+			ExpectedPrefix: "saas-example-operator: abc -> def",
+			ExpectedSuffix: "saas-example-operator: abc -> def",
+		},
+		{
+			Name:           "Default format with Jira",
+			Data:           withJira,
+			ExpectedPrefix: "[OSD-1234] Promote saas-example-operator to def",
+			ExpectedSuffix: "Jira: https://issues.redhat.com/browse/OSD-1234",
+		},
+		{
+			Name:           "Custom template with Jira",
+			Template:       "{{.Jira}} promote {{.Service}}",
+			Data:           withJira,
+			ExpectedPrefix: "OSD-1234 promote saas-example-operator",
+			ExpectedSuffix: "Jira: https://issues.redhat.com/browse/OSD-1234",
+		},
+	}
+
+	for _, test := range tests {
+		message, err := renderCommitMessage(test.Template, test.Data)
+		if err != nil {
+			t.Errorf("Test '%s' failed. Unexpected error: %v", test.Name, err)
+			continue
+		}
+		if !strings.HasPrefix(message, test.ExpectedPrefix) || !strings.HasSuffix(message, test.ExpectedSuffix) {
+			t.Errorf("Test '%s' failed. Unexpected commit message:\n%s", test.Name, message)
+		}
+	}
+}
+
+func TestRenderCommitMessageUnknownField(t *testing.T) {
+	_, err := renderCommitMessage("{{.Unknown}}", commitMessageData{})
+	if err == nil {
+		t.Errorf("expected an error rendering a template with an unknown field")
+	}
+}
+
+func TestValidateJiraKey(t *testing.T) {
+	for _, key := range []string{"", "OSD-1234", "SREP2-1"} {
+		if err := validateJiraKey(key); err != nil {
+			t.Errorf("expected '%s' to be a valid Jira key, got: %v", key, err)
+		}
+	}
+	for _, key := range []string{"osd-1234", "OSD1234", "OSD-", "https://issues.redhat.com/browse/OSD-1234"} {
+		if err := validateJiraKey(key); err == nil {
+			t.Errorf("expected '%s' to be an invalid Jira key", key)
+		}
+	}
+}
+
+func TestTargetChangeRows(t *testing.T) {
+	allTargets := []git.SaasTarget{
+		{ResourceTemplateIndex: 0, TargetIndex: 0, ResourceTemplate: "example", Namespace: "hivei01ue1", Ref: "master"},
+		{ResourceTemplateIndex: 0, TargetIndex: 1, ResourceTemplate: "example", Namespace: "hivep01ue1", Ref: "abc"},
+		{ResourceTemplateIndex: 0, TargetIndex: 2, ResourceTemplate: "example", Namespace: "hivep02ue1", Ref: "def"},
+	}
+	promotedTargets := []git.SaasTarget{allTargets[1], allTargets[2]}
+
+	expected := []string{"unchanged", "abc -> def", "unchanged"}
+	rows := targetChangeRows(allTargets, promotedTargets, "def")
+	if len(rows) != len(expected) {
+		t.Fatalf("expected %d rows, got %d", len(expected), len(rows))
+	}
+	for i, row := range rows {
+		if row[1] != allTargets[i].Namespace || row[2] != expected[i] {
+			t.Errorf("expected %s to be '%s', got '%s'", allTargets[i].Namespace, expected[i], row[2])
+		}
+	}
+}
+
+func TestGetServiceNamesListsOnce(t *testing.T) {
+	appInterface := git.AppInterface{GitDirectory: t.TempDir()}
+	saasDir := filepath.Join(appInterface.GitDirectory, OSDSaasDir)
+	err := os.MkdirAll(filepath.Join(saasDir, "saas-example-operator"), 0700)
+	if err != nil {
+		t.Fatalf("failed to create SAAS directory: %v", err)
+	}
+	err = os.WriteFile(filepath.Join(saasDir, "saas-other-operator.yaml"), []byte{}, 0600)
+	if err != nil {
+		t.Fatalf("failed to create SAAS file: %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		_, err = GetServiceNames(appInterface, OSDSaasDir)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	var listed int
+	for _, service := range ServicesSlice {
+		if service == "saas-example-operator" || service == "saas-other-operator" {
+			listed++
+		}
+	}
+	if listed != 2 {
+		t.Errorf("expected each service to be listed once, got %v", ServicesSlice)
+	}
+	if ServicesFilesMap["saas-other-operator"] != filepath.Join(saasDir, "saas-other-operator.yaml") {
+		t.Errorf("expected saas-other-operator to map to its SAAS file, got %s", ServicesFilesMap["saas-other-operator"])
+	}
+}
+
+func TestGetSaasDirYml(t *testing.T) {
+	appInterface := git.AppInterface{GitDirectory: "testdata/app-interface"}
+	_, err := GetServiceNames(appInterface, OSDSaasDir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		service  string
+		expected string
+	}{
+		{
+			name:     ".yml SAAS file",
+			service:  "saas-yml-operator",
+			expected: filepath.Join("testdata/app-interface", OSDSaasDir, "saas-yml-operator.yml"),
+		},
+		{
+			name:     ".yml deploy file",
+			service:  "saas-dir-operator",
+			expected: filepath.Join("testdata/app-interface", OSDSaasDir, "saas-dir-operator", "deploy.yml"),
+		},
+	}
+
+	for _, test := range tests {
+		serviceName, err := ValidateServiceName(ServicesSlice, test.service)
+		if err != nil {
+			t.Errorf("Test '%s' failed. Unexpected error: %v", test.name, err)
+			continue
+		}
+		saasDir, err := GetSaasDir(serviceName, true, false)
+		if err != nil {
+			t.Errorf("Test '%s' failed. Unexpected error: %v", test.name, err)
+			continue
+		}
+		if saasDir != test.expected {
+			t.Errorf("Test '%s' failed. Expected %s, got %s", test.name, test.expected, saasDir)
+		}
+	}
+}
@@ -85,15 +85,10 @@ func GetServiceLogsSince(clusterID string, timeSince time.Time, allMessages bool
 
 func FetchServiceLogs(clusterID string, allMessages bool, internalOnly bool) (*v1.ClustersClusterLogsListResponse, error) {
 	// Create OCM client to talk to cluster API
-	ocmClient, err := utils.CreateConnection()
+	ocmClient, err := utils.SharedConnection()
 	if err != nil {
 		return nil, err
 	}
-	defer func() {
-		if err := ocmClient.Close(); err != nil {
-			fmt.Printf("Cannot close the ocmClient (possible memory leak): %q", err)
-		}
-	}()
 
 	// Use the OCM client to retrieve clusters
 	clusters := utils.GetClusters(ocmClient, []string{clusterID})
@@ -168,15 +168,10 @@ func (o *PostCmdOptions) Run() error {
 
 	// Create an OCM client to talk to the cluster API
 	// the user has to be logged in (e.g. 'ocm login')
-	ocmClient, err := ocmutils.CreateConnection()
+	ocmClient, err := ocmutils.SharedConnection()
 	if err != nil {
 		return err
 	}
-	defer func() {
-		if err := ocmClient.Close(); err != nil {
-			log.Errorf("Cannot close the ocmClient (possible memory leak): %q", err)
-		}
-	}()
 
 	// Merge OCM filters from all custom filter-related flags
 	if o.filtersFromFile != "" {
@@ -1,24 +1,63 @@
 package globalflags
 
 import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
 	awsSdk "github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/openshift/osdctl/pkg/provider/aws"
+	"github.com/openshift/osdctl/pkg/utils"
 	"github.com/spf13/cobra"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 )
 
+// outputFormatsAnnotation is the command annotation holding the comma separated --output formats a command supports
+const outputFormatsAnnotation = "osdctl/output-formats"
+
+// DefaultOutputFormats are the --output formats offered for commands that don't set their own
+var DefaultOutputFormats = []string{"json", "yaml", "env"}
+
 // GlobalOptions defines all available commands
 type GlobalOptions struct {
 	Output           string
 	SkipVersionCheck bool
 	NoAwsProxy       bool
+	OCMTimeout       time.Duration
+	LogLevel         string
+	Timings          bool
 }
 
 // AddGlobalFlags adds the Global Flags to the root command
 func AddGlobalFlags(cmd *cobra.Command, opts *GlobalOptions) {
-	cmd.PersistentFlags().StringVarP(&opts.Output, "output", "o", "", "Valid formats are ['', 'json', 'yaml', 'env']")
+	cmd.PersistentFlags().StringVarP(&opts.Output, "output", "o", "", "Valid formats are ['', 'json', 'yaml', 'env']. Some commands support other formats, see their help")
 	cmd.PersistentFlags().BoolVarP(&opts.SkipVersionCheck, "skip-version-check", "S", false, "skip checking to see if this is the most recent release")
 	cmd.PersistentFlags().BoolVar(&opts.NoAwsProxy, aws.NoProxyFlag, false, "Don't use the configured `aws_proxy` value")
+	cmd.PersistentFlags().DurationVar(&opts.OCMTimeout, utils.OCMTimeoutFlag, utils.DefaultOCMTimeout, "Cancel any OCM request taking longer than this. Set to 0 to disable")
+	cmd.PersistentFlags().StringVar(&opts.LogLevel, "log-level", "info", "Log level, one of ['debug', 'info', 'warn', 'error']. 'debug' shows the OCM requests and git operations being run")
+	cmd.PersistentFlags().BoolVar(&opts.Timings, utils.TimingsFlag, false, "Print how long the phases of the command took to stderr once it completes")
+	_ = cmd.RegisterFlagCompletionFunc("output", func(cmd *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+		return OutputFormats(cmd), cobra.ShellCompDirectiveNoFileComp
+	})
+}
+
+// SetOutputFormats records the --output formats supported by a command, so they are offered by shell completion.
+// The command is expected to validate the flag against the same formats with ValidateOutput
+func SetOutputFormats(cmd *cobra.Command, formats ...string) {
+	if cmd.Annotations == nil {
+		cmd.Annotations = map[string]string{}
+	}
+	cmd.Annotations[outputFormatsAnnotation] = strings.Join(formats, ",")
+}
+
+// OutputFormats returns the --output formats supported by a command, falling back to DefaultOutputFormats
+func OutputFormats(cmd *cobra.Command) []string {
+	formats, ok := cmd.Annotations[outputFormatsAnnotation]
+	if !ok {
+		return DefaultOutputFormats
+	}
+	return strings.Split(formats, ",")
 }
 
 // GetFlags adds the kubeFlags we care about and adds the flags from the provided command
@@ -37,3 +76,11 @@ func GetFlags(cmd *cobra.Command) *genericclioptions.ConfigFlags {
 	flags.AddFlags(cmd.PersistentFlags())
 	return flags
 }
+
+// ValidateOutput returns an error if the output format is not one of the formats supported by a command
+func ValidateOutput(output string, formats ...string) error {
+	if slices.Contains(formats, output) {
+		return nil
+	}
+	return fmt.Errorf("invalid output format '%s', valid formats are ['%s']", output, strings.Join(formats, "', '"))
+}
@@ -1,11 +1,14 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"os"
 
 	"github.com/openshift/osdctl/cmd"
 	"github.com/openshift/osdctl/pkg/osdctlConfig"
+	"github.com/openshift/osdctl/pkg/utils"
+	"github.com/spf13/viper"
 
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 )
@@ -20,10 +23,22 @@ func main() {
 
 	command := cmd.NewCmdRoot(genericclioptions.IOStreams{In: os.Stdin, Out: os.Stdout, ErrOut: os.Stderr})
 
-	if err := command.Execute(); err != nil {
-		_, err := fmt.Fprintf(os.Stderr, "%v\n", err)
-		if err != nil {
-			fmt.Println("Error while printing to stderr: ", err.Error())
+	err = command.Execute()
+	// Printed here rather than in a post-run hook so the timings of failed commands are shown too
+	if viper.GetBool(utils.TimingsFlag) {
+		utils.Timings.Print(os.Stderr)
+	}
+	if closeErr := utils.CloseSharedConnection(); closeErr != nil {
+		fmt.Fprintf(os.Stderr, "Cannot close the OCM connection: %v\n", closeErr)
+	}
+	if err != nil {
+		_, printErr := fmt.Fprintf(os.Stderr, "%v\n", err)
+		if printErr != nil {
+			fmt.Println("Error while printing to stderr: ", printErr.Error())
+		}
+		var exitErr interface{ ExitCode() int }
+		if errors.As(err, &exitErr) {
+			os.Exit(exitErr.ExitCode())
 		}
 		os.Exit(1)
 	}
@@ -129,11 +129,10 @@ func GenerateJumpRoleCredentials(client aws.Client, region, sessionName string)
 	}
 
 	jumpRoleKey := ProdJumproleConfigKey
-	conn, err := utils.CreateConnection()
+	conn, err := utils.SharedConnection()
 	if err != nil {
 		return nil, err
 	}
-	defer conn.Close()
 	currentEnv := utils.GetCurrentOCMEnv(conn)
 	if currentEnv == "stage" || currentEnv == "integration" {
 		jumpRoleKey = StageJumproleConfigKey
@@ -256,11 +255,10 @@ func GenerateNonCCSClusterAWSClient(ocmClient *sdk.Connection, awsClient aws.Cli
 // If an AWS profile name is not specified, this function will also read the AWS_PROFILE environment
 // variable or use the default AWS profile.
 func GenerateAWSClientForCluster(awsProfile string, clusterID string) (aws.Client, error) {
-	ocmClient, err := utils.CreateConnection()
+	ocmClient, err := utils.SharedConnection()
 	if err != nil {
 		return nil, err
 	}
-	defer ocmClient.Close()
 
 	cluster, err := utils.GetClusterAnyStatus(ocmClient, clusterID)
 	if err != nil {
@@ -6,6 +6,7 @@ package pagerduty
 import (
 	"context"
 	"fmt"
+	"os"
 	"sort"
 	"strings"
 	"time"
@@ -19,6 +20,27 @@ const (
 	PagerDutyTeamIDsKey          = "team_ids"
 )
 
+const (
+	// DefaultSubdomain is the PagerDuty account subdomain service links point to
+	DefaultSubdomain = "redhat"
+	// SubdomainEnv is the environment variable overriding DefaultSubdomain
+	SubdomainEnv = "PD_SUBDOMAIN"
+)
+
+// Subdomain returns the PagerDuty account subdomain set in SubdomainEnv, falling back to DefaultSubdomain
+func Subdomain() string {
+	if subdomain := os.Getenv(SubdomainEnv); subdomain != "" {
+		return subdomain
+	}
+	return DefaultSubdomain
+}
+
+// ServiceURL returns the web URL of a PagerDuty service in the given account subdomain. The URLs of the services of a
+// cluster are returned by GetClusterServiceURLs
+func ServiceURL(subdomain, serviceID string) string {
+	return fmt.Sprintf("https://%s.pagerduty.com/service-directory/%s", subdomain, serviceID)
+}
+
 type IncidentOccurrenceTracker struct {
 	IncidentName   string
 	Count          int
@@ -99,6 +121,39 @@ func (c *client) GetPDServiceIDs() ([]string, error) {
 	return serviceIDS, nil
 }
 
+// ServiceLink is a PagerDuty service along with its web URL
+type ServiceLink struct {
+	ID  string
+	URL string
+}
+
+// GetClusterServiceURLs looks up the PagerDuty services of a cluster by its OCM ID and external ID, falling back to
+// the base domain of the client when neither matches a service, and returns them with their web URLs in the given
+// subdomain. No services are returned when none matches
+func (c *client) GetClusterServiceURLs(clusterID, externalID, subdomain string) ([]ServiceLink, error) {
+	var services []ServiceLink
+	seen := map[string]bool{}
+	for _, query := range []string{clusterID, externalID, c.baseDomain} {
+		if query == "" || (query == c.baseDomain && len(services) > 0) {
+			continue
+		}
+
+		lsResponse, err := c.pdclient.ListServicesWithContext(context.TODO(), pd.ListServiceOptions{Query: query, TeamIDs: c.teamIds})
+		if err != nil {
+			return nil, fmt.Errorf("failed to ListServicesWithContext for %s: %w", query, err)
+		}
+		for _, service := range lsResponse.Services {
+			if seen[service.ID] {
+				continue
+			}
+			seen[service.ID] = true
+			services = append(services, ServiceLink{ID: service.ID, URL: ServiceURL(subdomain, service.ID)})
+		}
+	}
+
+	return services, nil
+}
+
 func (c *client) GetFiringAlertsForCluster(pdServiceIDs []string) (map[string][]pd.Incident, error) {
 	incidents := map[string][]pd.Incident{}
 
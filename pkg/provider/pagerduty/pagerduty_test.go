@@ -2,6 +2,7 @@ package pagerduty
 
 import (
 	"fmt"
+	"os"
 
 	pd "github.com/PagerDuty/go-pagerduty"
 	. "github.com/onsi/ginkgo"
@@ -90,6 +91,50 @@ var _ = Describe("Tests the Pagerduty Provider", func() {
 			})
 		})
 
+		Context("GetClusterServiceURLs", func() {
+			It("Looks up the services by the cluster IDs", func() {
+				m := pdMock.NewMockpdClientInterface(ctrl)
+				m.EXPECT().ListServicesWithContext(gomock.Any(), pd.ListServiceOptions{Query: "cluster-id"}).Return(&pd.ListServiceResponse{Services: []pd.Service{{APIObject: pd.APIObject{ID: "abcd"}}}}, nil)
+				m.EXPECT().ListServicesWithContext(gomock.Any(), pd.ListServiceOptions{Query: "external-id"}).Return(&pd.ListServiceResponse{Services: []pd.Service{{APIObject: pd.APIObject{ID: "abcd"}}, {APIObject: pd.APIObject{ID: "1234"}}}}, nil)
+				pdProvider.WithBaseDomain("example.devshift.org").pdclient = m
+
+				urls, err := pdProvider.GetClusterServiceURLs("cluster-id", "external-id", "example")
+				Expect(err).To(BeNil())
+				Expect(urls).To(Equal([]ServiceLink{
+					{ID: "abcd", URL: "https://example.pagerduty.com/service-directory/abcd"},
+					{ID: "1234", URL: "https://example.pagerduty.com/service-directory/1234"},
+				}))
+			})
+			It("Falls back to the base domain when no service matches the cluster IDs", func() {
+				m := pdMock.NewMockpdClientInterface(ctrl)
+				m.EXPECT().ListServicesWithContext(gomock.Any(), pd.ListServiceOptions{Query: "cluster-id", TeamIDs: []string{"team"}}).Return(&pd.ListServiceResponse{}, nil)
+				m.EXPECT().ListServicesWithContext(gomock.Any(), pd.ListServiceOptions{Query: "example.devshift.org", TeamIDs: []string{"team"}}).Return(&pd.ListServiceResponse{Services: []pd.Service{{APIObject: pd.APIObject{ID: "abcd"}}}}, nil)
+				pdProvider.WithBaseDomain("example.devshift.org").WithTeamIdList([]string{"team"}).pdclient = m
+
+				urls, err := pdProvider.GetClusterServiceURLs("cluster-id", "", "example")
+				Expect(err).To(BeNil())
+				Expect(urls).To(Equal([]ServiceLink{{ID: "abcd", URL: "https://example.pagerduty.com/service-directory/abcd"}}))
+			})
+			It("Returns no services when none matches the cluster", func() {
+				m := pdMock.NewMockpdClientInterface(ctrl)
+				m.EXPECT().ListServicesWithContext(gomock.Any(), gomock.Any()).Return(&pd.ListServiceResponse{}, nil).Times(2)
+				pdProvider.pdclient = m
+
+				urls, err := pdProvider.GetClusterServiceURLs("cluster-id", "external-id", "example")
+				Expect(urls).To(BeEmpty())
+				Expect(err).To(BeNil())
+			})
+			It("Returns an error from the pd client if there's an error with the request", func() {
+				m := pdMock.NewMockpdClientInterface(ctrl)
+				m.EXPECT().ListServicesWithContext(gomock.Any(), gomock.Any()).Return(nil, fmt.Errorf("Some Error"))
+				pdProvider.pdclient = m
+
+				_, err := pdProvider.GetClusterServiceURLs("cluster-id", "", "example")
+				Expect(err).To(Not(BeNil()))
+				Expect(err.Error()).To(ContainSubstring("Some Error"))
+			})
+		})
+
 		Context("GetFiringAlertsForCluster", func() {
 			var emptyIncResponse, singleIncResponse, multipleIncResponse, multiplePageIncResponse *pd.ListIncidentsResponse
 
@@ -193,3 +238,20 @@ var _ = Describe("Tests the Pagerduty Provider", func() {
 		})
 	})
 })
+
+var _ = Describe("PagerDuty service links", func() {
+	AfterEach(func() {
+		Expect(os.Unsetenv(SubdomainEnv)).To(Succeed())
+	})
+	It("Should build the service URL in the given subdomain", func() {
+		Expect(ServiceURL("example", "P123ABC")).To(Equal("https://example.pagerduty.com/service-directory/P123ABC"))
+	})
+	It("Should default the subdomain", func() {
+		Expect(os.Unsetenv(SubdomainEnv)).To(Succeed())
+		Expect(Subdomain()).To(Equal(DefaultSubdomain))
+	})
+	It("Should read the subdomain from the environment", func() {
+		Expect(os.Setenv(SubdomainEnv, "example")).To(Succeed())
+		Expect(Subdomain()).To(Equal("example"))
+	})
+})
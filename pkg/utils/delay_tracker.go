@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"os"
 	"time"
+
+	log "github.com/sirupsen/logrus"
 )
 
 type DelayTracker struct {
@@ -16,16 +18,22 @@ func StartDelayTracker(verbose bool, action string) *DelayTracker {
 	dt := DelayTracker{
 		verbose: verbose,
 		action:  action,
+		start:   time.Now(),
 	}
 	if dt.verbose {
-		dt.start = time.Now()
 		fmt.Fprintf(os.Stderr, "Getting %s...\n", dt.action)
+	} else {
+		log.Debugf("Getting %s...", dt.action)
 	}
 	return &dt
 }
 
+// End reports how long the action took and records it in Timings
 func (dt *DelayTracker) End() {
+	Timings.Record(dt.action, time.Since(dt.start))
 	if dt.verbose {
 		fmt.Fprintf(os.Stderr, "Got %s within %s\n", dt.action, time.Since(dt.start))
+	} else {
+		log.Debugf("Got %s within %s", dt.action, time.Since(dt.start))
 	}
 }
@@ -9,11 +9,13 @@ import (
 	"path/filepath"
 	"regexp"
 	"strings"
+	"sync"
 
 	"github.com/aws/aws-sdk-go-v2/aws/arn"
 	"github.com/google/uuid"
 	sdk "github.com/openshift-online/ocm-sdk-go"
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/spf13/viper"
 )
 
 const ClusterServiceClusterSearch = "id = '%s' or name = '%s' or external_id = '%s'"
@@ -262,6 +264,8 @@ func getOcmConfiguration(ocmConfigLoader func() (*Config, error)) (*Config, erro
 	return config, nil
 }
 
+// CreateConnection builds a new OCM connection from the local OCM configuration. Commands should use SharedConnection
+// instead, which reuses a single connection for the whole process
 func CreateConnection() (*sdk.Connection, error) {
 	ocmConfigError := "Unable to load OCM config\nLogin with 'ocm login' or set OCM_TOKEN, OCM_URL and OCM_REFRESH_TOKEN environment variables"
 
@@ -286,6 +290,7 @@ func CreateConnection() (*sdk.Connection, error) {
 	connectionBuilder.URL(gatewayURL)
 
 	connectionBuilder.Client(config.ClientID, config.ClientSecret)
+	connectionBuilder.TransportWrapper(withOCMTimeout(viper.GetDuration(OCMTimeoutFlag)))
 
 	connection, err := connectionBuilder.Build()
 
@@ -299,6 +304,40 @@ func CreateConnection() (*sdk.Connection, error) {
 	return connection, nil
 }
 
+// sharedConnection is the OCM connection reused by every command of the process, see SharedConnection
+var sharedConnection struct {
+	sync.Mutex
+	connection *sdk.Connection
+}
+
+// SharedConnection returns the OCM connection shared by the whole process, creating it on first use. This avoids
+// authenticating again for every OCM call of a command. Callers must not close it, CloseSharedConnection is called once
+// the command exits
+func SharedConnection() (*sdk.Connection, error) {
+	sharedConnection.Lock()
+	defer sharedConnection.Unlock()
+	if sharedConnection.connection == nil {
+		connection, err := CreateConnection()
+		if err != nil {
+			return nil, err
+		}
+		sharedConnection.connection = connection
+	}
+	return sharedConnection.connection, nil
+}
+
+// CloseSharedConnection closes the connection returned by SharedConnection, if one was created
+func CloseSharedConnection() error {
+	sharedConnection.Lock()
+	defer sharedConnection.Unlock()
+	if sharedConnection.connection == nil {
+		return nil
+	}
+	err := sharedConnection.connection.Close()
+	sharedConnection.connection = nil
+	return err
+}
+
 func GetSupportRoleArnForCluster(ocmClient *sdk.Connection, clusterID string) (string, error) {
 
 	clusterResponse, err := ocmClient.ClustersMgmt().V1().Clusters().Cluster(clusterID).Get().Send()
@@ -368,11 +407,10 @@ func IsClusterCCS(ocmClient *sdk.Connection, clusterID string) (bool, error) {
 // Returns the hive shard corresponding to a cluster
 // e.g. https://api.<hive_cluster>.byo5.p1.openshiftapps.com:6443
 func GetHiveShard(clusterID string) (string, error) {
-	connection, err := CreateConnection()
+	connection, err := SharedConnection()
 	if err != nil {
 		return "", err
 	}
-	defer connection.Close()
 
 	shardPath, err := connection.ClustersMgmt().V1().Clusters().
 		Cluster(clusterID).
@@ -398,11 +436,10 @@ func GetHiveShard(clusterID string) (string, error) {
 }
 
 func GetHiveCluster(clusterId string) (*cmv1.Cluster, error) {
-	conn, err := CreateConnection()
+	conn, err := SharedConnection()
 	if err != nil {
 		return nil, err
 	}
-	defer conn.Close()
 
 	provisionShard, err := conn.ClustersMgmt().V1().Clusters().
 		Cluster(clusterId).
@@ -434,11 +471,10 @@ func GetHiveCluster(clusterId string) (*cmv1.Cluster, error) {
 
 // GetManagementCluster returns the OCM Cluster object for a provided clusterId
 func GetManagementCluster(clusterId string) (*cmv1.Cluster, error) {
-	conn, err := CreateConnection()
+	conn, err := SharedConnection()
 	if err != nil {
 		return nil, err
 	}
-	defer conn.Close()
 
 	hypershiftResp, err := conn.ClustersMgmt().V1().Clusters().
 		Cluster(clusterId).
@@ -458,11 +494,10 @@ func GetManagementCluster(clusterId string) (*cmv1.Cluster, error) {
 
 // GetServiceCluster returns the hypershift Service Cluster object for a provided HCP clusterId
 func GetServiceCluster(clusterId string) (*cmv1.Cluster, error) {
-	conn, err := CreateConnection()
+	conn, err := SharedConnection()
 	if err != nil {
 		return nil, err
 	}
-	defer conn.Close()
 
 	var svcClusterName, mgmtClusterName string
 
@@ -506,11 +541,10 @@ func GetServiceCluster(clusterId string) (*cmv1.Cluster, error) {
 
 // Sanity Check for MC Cluster
 func IsManagementCluster(clusterID string) (isMC bool, err error) {
-	conn, err := CreateConnection()
+	conn, err := SharedConnection()
 	if err != nil {
 		return false, err
 	}
-	defer conn.Close()
 	collection := conn.ClustersMgmt().V1().Clusters()
 	// Get the labels externally available for the cluster
 	resource := collection.Cluster(clusterID).ExternalConfiguration().Labels()
@@ -537,11 +571,10 @@ func IsManagementCluster(clusterID string) (isMC bool, err error) {
 }
 
 func IsHostedCluster(clusterID string) (bool, error) {
-	conn, err := CreateConnection()
+	conn, err := SharedConnection()
 	if err != nil {
 		return false, err
 	}
-	defer conn.Close()
 
 	cluster := conn.ClustersMgmt().V1().Clusters().Cluster(clusterID)
 	res, err := cluster.Get().Send()
@@ -553,11 +586,10 @@ func IsHostedCluster(clusterID string) (bool, error) {
 }
 
 func GetHCPNamespace(clusterId string) (namespace string, err error) {
-	conn, err := CreateConnection()
+	conn, err := SharedConnection()
 	if err != nil {
 		return "", err
 	}
-	defer conn.Close()
 
 	hypershiftResp, err := conn.ClustersMgmt().V1().Clusters().
 		Cluster(clusterId).
@@ -576,11 +608,10 @@ func GetHCPNamespace(clusterId string) (namespace string, err error) {
 }
 
 func GetDynatraceURLFromLabel(clusterID string) (url string, err error) {
-	conn, err := CreateConnection()
+	conn, err := SharedConnection()
 	if err != nil {
 		return "", err
 	}
-	defer conn.Close()
 	subscription, err := GetSubscription(conn, clusterID)
 	if err != nil {
 		return "", err
@@ -0,0 +1,54 @@
+package utils
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"time"
+)
+
+const (
+	// OCMTimeoutFlag is the global flag setting the deadline of each OCM request
+	OCMTimeoutFlag = "ocm-timeout"
+	// DefaultOCMTimeout is the deadline of each OCM request unless overridden by OCMTimeoutFlag
+	DefaultOCMTimeout = 2 * time.Minute
+)
+
+// timeoutTransport cancels requests that don't complete, including reading their body, within the timeout
+type timeoutTransport struct {
+	base    http.RoundTripper
+	timeout time.Duration
+}
+
+func (t *timeoutTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx, cancel := context.WithTimeout(req.Context(), t.timeout)
+	resp, err := t.base.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+// cancelOnClose releases the context of a request once its response body is closed
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (c *cancelOnClose) Close() error {
+	defer c.cancel()
+	return c.ReadCloser.Close()
+}
+
+// withOCMTimeout wraps the transport of OCM connections so each request is cancelled after the timeout. A timeout
+// of zero or less disables it
+func withOCMTimeout(timeout time.Duration) func(http.RoundTripper) http.RoundTripper {
+	return func(base http.RoundTripper) http.RoundTripper {
+		if timeout <= 0 {
+			return base
+		}
+		return &timeoutTransport{base: base, timeout: timeout}
+	}
+}
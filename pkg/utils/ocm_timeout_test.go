@@ -0,0 +1,43 @@
+package utils
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestWithOCMTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow" {
+			select {
+			case <-r.Context().Done():
+			case <-time.After(time.Second):
+			}
+		}
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: withOCMTimeout(100 * time.Millisecond)(http.DefaultTransport)}
+
+	resp, err := client.Get(server.URL + "/fast")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	body, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil || string(body) != "ok" {
+		t.Errorf("expected to read the response body, got '%s': %v", body, err)
+	}
+
+	_, err = client.Get(server.URL + "/slow")
+	if err == nil {
+		t.Errorf("expected the slow request to time out")
+	}
+
+	if transport := withOCMTimeout(0)(http.DefaultTransport); transport != http.DefaultTransport {
+		t.Errorf("expected a zero timeout to leave the transport unchanged")
+	}
+}
@@ -15,6 +15,7 @@ import (
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	v1 "github.com/openshift-online/ocm-sdk-go/servicelogs/v1"
 	"github.com/openshift/osdctl/pkg/printer"
+	"github.com/openshift/osdctl/pkg/provider/pagerduty"
 )
 
 const (
@@ -54,17 +55,18 @@ func PrintServiceLogs(serviceLogs []*v1.LogEntry, verbose bool, sinceDays int) {
 	}
 }
 
-func PrintPDAlerts(incidents map[string][]pd.Incident, serviceIDs []string) {
+func PrintPDAlerts(incidents map[string][]pd.Incident, services []pagerduty.ServiceLink) {
 	var name = "PagerDuty Alerts"
 	fmt.Println(delimiter + name)
 
-	if len(serviceIDs) == 0 {
+	if len(services) == 0 {
 		fmt.Println("No PD Service Found")
 		return
 	}
 
-	for _, ID := range serviceIDs {
-		fmt.Printf("Service: https://redhat.pagerduty.com/service-directory/%s\n", ID)
+	for _, service := range services {
+		ID := service.ID
+		fmt.Printf("Service: %s\n", service.URL)
 
 		tableHasContent := false
 		table := printer.NewTablePrinter(os.Stdout, 20, 1, 3, ' ')
@@ -0,0 +1,53 @@
+package utils
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// TimingsFlag is the global flag printing how long the phases of a command took once it completes
+const TimingsFlag = "timings"
+
+// Timings is the Stopwatch shared by the phases of the running command
+var Timings = &Stopwatch{}
+
+// Stopwatch records how long the phases of a command took. It is safe for concurrent use, as phases may run in parallel
+type Stopwatch struct {
+	mu     sync.Mutex
+	phases []phaseTiming
+}
+
+type phaseTiming struct {
+	name     string
+	duration time.Duration
+}
+
+// Start begins timing a phase and returns the func recording it, i.e. `defer utils.Timings.Start("git clone")()`
+func (s *Stopwatch) Start(phase string) func() {
+	start := time.Now()
+	return func() {
+		s.Record(phase, time.Since(start))
+	}
+}
+
+// Record adds a phase that took the given duration
+func (s *Stopwatch) Record(phase string, duration time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.phases = append(s.phases, phaseTiming{name: phase, duration: duration})
+}
+
+// Print writes the recorded phases, in the order they completed, to w
+func (s *Stopwatch) Print(w io.Writer) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.phases) == 0 {
+		return
+	}
+	fmt.Fprintln(w, "Timings:")
+	for _, phase := range s.phases {
+		fmt.Fprintf(w, "  %-40s %s\n", phase.name, phase.duration.Round(time.Millisecond))
+	}
+}
@@ -0,0 +1,32 @@
+package utils
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStopwatchPrint(t *testing.T) {
+	var out bytes.Buffer
+	s := &Stopwatch{}
+	s.Print(&out)
+	if out.Len() != 0 {
+		t.Errorf("expected no output without recorded phases, got '%s'", out.String())
+	}
+
+	s.Record("OCM Clusters", 1500*time.Millisecond)
+	s.Start("git clone")()
+	s.Print(&out)
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected a header and 2 phases, got '%s'", out.String())
+	}
+	if !strings.Contains(lines[1], "OCM Clusters") || !strings.HasSuffix(lines[1], "1.5s") {
+		t.Errorf("expected the first phase to be OCM Clusters taking 1.5s, got '%s'", lines[1])
+	}
+	if !strings.Contains(lines[2], "git clone") {
+		t.Errorf("expected the second phase to be git clone, got '%s'", lines[2])
+	}
+}